@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+	"github.com/vfc2/tax-calculator/internal/tax"
+)
+
+// Size of the chart and of the margins around its plot, in SVG units.
+const (
+	chartWidth   = 640
+	chartHeight  = 320
+	chartLeft    = 48
+	chartRight   = 16
+	chartTop     = 16
+	chartBottom  = 32
+	chartXTicks  = 5
+	chartYTicks  = 5
+	chartMaxRate = 1.0
+)
+
+type ChartTick struct {
+	Position float64
+	Label    string
+}
+
+type ChartArea struct {
+	Name string
+	X    float64
+	W    float64
+	H    float64
+}
+
+type ChartMarker struct {
+	X         float64
+	Y         float64
+	Effective float64
+	Marginal  float64
+	Income    money.Money
+}
+
+// RateChart is the SVG geometry of a tax.RateCurve.
+type RateChart struct {
+	Width     float64
+	Height    float64
+	Left      float64
+	Right     float64
+	Top       float64
+	Bottom    float64
+	Effective string
+	Marginal  string
+	Cliffs    []ChartArea
+	XTicks    []ChartTick
+	YTicks    []ChartTick
+	Current   *ChartMarker
+}
+
+func newRateChart(curve tax.RateCurve, current tax.RatePoint) RateChart {
+	c := RateChart{
+		Width:  chartWidth,
+		Height: chartHeight,
+		Left:   chartLeft,
+		Right:  chartWidth - chartRight,
+		Top:    chartTop,
+		Bottom: chartHeight - chartBottom,
+	}
+	if len(curve.Points) == 0 {
+		return c
+	}
+
+	from := curve.Points[0].Income
+	to := curve.Points[len(curve.Points)-1].Income
+	maxRate := chartMaxRate
+	for _, p := range curve.Points {
+		maxRate = max(maxRate, p.Marginal)
+	}
+
+	x := func(m money.Money) float64 {
+		if to == from {
+			return c.Left
+		}
+		return c.Left + float64(m-from)/float64(to-from)*(c.Right-c.Left)
+	}
+	y := func(rate float64) float64 {
+		return c.Bottom - max(rate, 0)/maxRate*(c.Bottom-c.Top)
+	}
+
+	var effective, marginal strings.Builder
+	for _, p := range curve.Points {
+		fmt.Fprintf(&effective, "%.1f,%.1f ", x(p.Income), y(p.Effective))
+		fmt.Fprintf(&marginal, "%.1f,%.1f ", x(p.Income), y(p.Marginal))
+	}
+	c.Effective = strings.TrimSpace(effective.String())
+	c.Marginal = strings.TrimSpace(marginal.String())
+
+	for _, cliff := range curve.Cliffs {
+		left := x(max(cliff.From, from))
+		right := x(min(cliff.To, to))
+		c.Cliffs = append(c.Cliffs, ChartArea{Name: cliff.Name, X: left, W: right - left, H: c.Bottom - c.Top})
+	}
+
+	for i := range chartXTicks + 1 {
+		m := from + (to-from)/chartXTicks*money.Money(i)
+		c.XTicks = append(c.XTicks, ChartTick{Position: x(m), Label: m.DisplayCurrency("£")})
+	}
+	for i := range chartYTicks + 1 {
+		rate := maxRate / chartYTicks * float64(i)
+		c.YTicks = append(c.YTicks, ChartTick{Position: y(rate), Label: fmt.Sprintf("%.0f%%", rate*100)})
+	}
+
+	if current.Income >= from && current.Income <= to && current.Income > 0 {
+		c.Current = &ChartMarker{
+			X:         x(current.Income),
+			Y:         y(current.Marginal),
+			Effective: current.Effective * 100,
+			Marginal:  current.Marginal * 100,
+			Income:    current.Income,
+		}
+	}
+
+	return c
+}
@@ -1,10 +1,19 @@
 package main
 
 import (
+	"errors"
 	"log/slog"
+	"math"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/vfc2/tax-calculator/internal/money"
+	"github.com/vfc2/tax-calculator/internal/tax"
+	"github.com/vfc2/tax-calculator/internal/taxcode"
 )
 
 type Handlers struct {
@@ -13,50 +22,727 @@ type Handlers struct {
 	models Models
 }
 
-type TaxInputValidation struct {
+type TaxInput struct {
+	Years  []tax.TaxYear
+	Year   tax.TaxYear
 	Errors map[string]string
 }
 
+type TaxOutput struct {
+	Year      tax.TaxYear
+	Breakdown tax.IncomeTaxBreakdown
+	Periods   []tax.PayPeriod
+	Query     string
+}
+
+type DirectorOutput struct {
+	Year       tax.TaxYear
+	Extraction tax.Extraction
+}
+
+// Splits returns the optimal split followed by the runners-up.
+func (o DirectorOutput) Splits() []tax.ExtractionSplit {
+	return append([]tax.ExtractionSplit{o.Extraction.Optimal}, o.Extraction.RunnersUp...)
+}
+
+type SelfEmployedOutput struct {
+	Year           tax.TaxYear
+	SelfAssessment tax.SelfAssessment
+}
+
+type ContractorOutput struct {
+	Year      tax.TaxYear
+	Contract  tax.Contract
+	Scenarios []tax.ContractScenario
+}
+
+type BonusOutput struct {
+	Year   tax.TaxYear
+	Salary money.Money
+	Bonus  tax.BonusBreakdown
+}
+
+// Months returns the regular month, the bonus month and the month with
+// the bonus sacrificed.
+func (o BonusOutput) Months() []tax.PayMonth {
+	return []tax.PayMonth{o.Bonus.Regular, o.Bonus.BonusMonth, o.Bonus.Sacrificed}
+}
+
+// MonthName returns the calendar month the tax month of the bonus mostly
+// falls in, tax month 1 starting on 6 April.
+func (o BonusOutput) MonthName() string {
+	return time.Month((o.Bonus.Month+2)%12 + 1).String()
+}
+
+// Percent returns a rate as a percentage, e.g. 34.43%.
+func (o BonusOutput) Percent(rate float64) string {
+	return formatPercent(rate)
+}
+
+// Percent returns a rate as a percentage, e.g. 6%.
+func (o SelfEmployedOutput) Percent(rate float64) string {
+	return formatPercent(rate)
+}
+
+// Amounts returns a yearly amount for each of the output periods.
+func (o TaxOutput) Amounts(m money.Money) []money.Money {
+	amounts := make([]money.Money, len(o.Periods))
+	for i, p := range o.Periods {
+		amounts[i] = p.FromAnnual(m)
+	}
+
+	return amounts
+}
+
+// Create a TaxInput for the tax years available, the current one being
+// selected.
+func (h Handlers) newTaxInput() TaxInput {
+	in := TaxInput{
+		Years:  h.models.years.Years(),
+		Year:   tax.TaxYearOf(time.Now()),
+		Errors: map[string]string{},
+	}
+	if _, ok := h.models.years[in.Year]; !ok {
+		in.Year = in.Years[len(in.Years)-1]
+	}
+
+	return in
+}
+
+// Percent returns a rate as a percentage, e.g. 8.75%.
+func (o TaxOutput) Percent(rate float64) string {
+	return formatPercent(rate)
+}
+
+// Render a form again with its validation errors, as a bad request.
+func (h Handlers) formError(w http.ResponseWriter, template string, val TaxInput) {
+	w.WriteHeader(http.StatusBadRequest)
+	h.views.render(w, template, "view", val, h.logger)
+}
+
 func (h Handlers) home(w http.ResponseWriter, r *http.Request) {
-	h.views.render(w, "home", "layout", nil, h.logger)
+	h.views.render(w, "home", "layout", h.newTaxInput(), h.logger)
 }
 
 func (h Handlers) inputPage(w http.ResponseWriter, r *http.Request) {
-	h.views.render(w, "tax_input", "view", nil, h.logger)
+	h.views.render(w, "tax_input", "view", h.newTaxInput(), h.logger)
 }
 
 func (h Handlers) outputPage(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		serverError(w, r, err, h.logger)
+		return
 	}
 
 	income := r.PostForm.Get("income")
-	period := r.PostForm.Get("period")
+	direction := r.PostForm.Get("direction")
+	period := tax.Frequency(r.PostForm.Get("period"))
+	hoursPerWeek := r.PostForm.Get("hours_per_week")
+	daysPerWeek := r.PostForm.Get("days_per_week")
+	weeksPerYear := r.PostForm.Get("weeks_per_year")
+	extraPayday := r.PostForm.Get("extra_payday") == "on"
+
+	val := h.newTaxInput()
 
 	wage, err := money.NewFromString(income)
 	if err != nil {
-		val := TaxInputValidation{
-			Errors: map[string]string{
-				"income": "The value must be a valid number.",
-			},
-		}
+		val.Errors["income"] = "The value must be a valid number."
+	}
 
-		h.views.render(w, "tax_input", "view", val, h.logger)
+	payPeriod := tax.PayPeriod{Frequency: period, ExtraPayday: extraPayday}
+	payPeriod.HoursPerWeek, err = parseOptionalFloat(hoursPerWeek)
+	if err != nil {
+		val.Errors["hours_per_week"] = "The value must be a valid number."
+	}
+	payPeriod.DaysPerWeek, err = parseOptionalFloat(daysPerWeek)
+	if err != nil {
+		val.Errors["days_per_week"] = "The value must be a valid number."
+	}
+	payPeriod.WeeksPerYear, err = parseOptionalFloat(weeksPerYear)
+	if err != nil {
+		val.Errors["weeks_per_year"] = "The value must be a valid number."
+	}
+	if err := payPeriod.Validate(); err != nil {
+		val.Errors["period"] = "The pay period or working pattern is invalid."
+	}
+
+	calc, ok := h.parseCalculator(r.PostForm, &val)
+	var opts tax.Options
+	if ok {
+		opts = parseOptions(r.PostForm, calc, val)
+		opts.ExtraPaydayWeeks = payPeriod.ExtraPaydayWeeks()
+	}
+
+	if len(val.Errors) > 0 {
+		h.formError(w, "tax_input", val)
 		return
 	}
 
-	switch period {
-	case "Month":
-		wage = wage.Mul(12)
-	case "Week":
-		wage = wage.Mul(52)
+	var breakdown tax.IncomeTaxBreakdown
+	switch direction {
+	case "net":
+		breakdown, err = calc.CalculateGross(wage, payPeriod, opts)
+	default:
+		breakdown, err = calc.CalculateTakeHome(payPeriod.ToAnnual(wage), opts)
+	}
+	if errors.Is(err, tax.ErrUnreachable) {
+		val.Errors["income"] = "No gross income gives this take home pay."
+		h.formError(w, "tax_input", val)
+		return
+	}
+	if errors.Is(err, tax.ErrContributionAboveIncome) {
+		val.Errors["pension_contribution"] = "The pension contribution cannot be larger than the income."
+		h.formError(w, "tax_input", val)
+		return
+	}
+	if err != nil {
+		serverError(w, r, err, h.logger)
+		return
+	}
+
+	query := url.Values{}
+	for k, v := range r.PostForm {
+		query[k] = v
+	}
+	query.Set("gross", breakdown.GrossIncome.Format(2))
+
+	out := TaxOutput{
+		Year:      val.Year,
+		Breakdown: breakdown,
+		Periods: []tax.PayPeriod{
+			{Frequency: tax.Annually},
+			{Frequency: tax.Monthly},
+			{Frequency: tax.Weekly},
+		},
+		Query: query.Encode(),
+	}
+	i := slices.IndexFunc(out.Periods, func(p tax.PayPeriod) bool { return p.Frequency == payPeriod.Frequency })
+	if i < 0 {
+		out.Periods = append(out.Periods, payPeriod)
+	} else {
+		out.Periods[i] = payPeriod
+	}
+
+	h.views.render(w, "tax_output", "view", out, h.logger)
+}
+
+func (h Handlers) ratesChart(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	val := h.newTaxInput()
+
+	calc, ok := h.parseCalculator(query, &val)
+	var opts tax.Options
+	if ok {
+		opts = parseOptions(query, calc, val)
 	}
 
-	tax, err := h.models.calc.CalculateTakeHome(wage, "A")
+	from, err := parseOptionalMoney(query.Get("from"), 0)
+	if err != nil {
+		val.Errors["from"] = "The value must be a valid number."
+	}
+	to, err := parseOptionalMoney(query.Get("to"), money.New(200000))
+	if err != nil {
+		val.Errors["to"] = "The value must be a valid number."
+	}
+	step, err := parseOptionalMoney(query.Get("step"), money.New(1000))
+	if err != nil {
+		val.Errors["step"] = "The value must be a valid number."
+	}
+	gross, err := parseOptionalMoney(query.Get("gross"), 0)
+	if err != nil {
+		val.Errors["gross"] = "The value must be a valid number."
+	}
+
+	if len(val.Errors) > 0 {
+		clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	curve, err := calc.CalculateRateCurve(from, to, step, opts)
+	if err != nil {
+		clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	current, err := calc.CalculateRates(gross, opts)
+	if errors.Is(err, tax.ErrContributionAboveIncome) {
+		clientError(w, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		serverError(w, r, err, h.logger)
+		return
+	}
+
+	h.views.render(w, "rate_chart", "view", newRateChart(curve, current), h.logger)
+}
+
+func (h Handlers) directorInputPage(w http.ResponseWriter, r *http.Request) {
+	h.views.render(w, "director_input", "view", h.newTaxInput(), h.logger)
+}
+
+func (h Handlers) directorOutputPage(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		serverError(w, r, err, h.logger)
+		return
+	}
+
+	val := h.newTaxInput()
+
+	profit, err := money.NewFromString(r.PostForm.Get("profit"))
+	if err != nil || profit <= 0 {
+		val.Errors["profit"] = "The value must be a valid positive number."
+	}
+
+	calc, ok := h.parseCalculator(r.PostForm, &val)
+	var opts tax.Options
+	if ok {
+		opts = parseOptions(r.PostForm, calc, val)
+	}
+
+	if len(val.Errors) > 0 {
+		h.formError(w, "director_input", val)
+		return
+	}
+
+	extraction, err := calc.OptimiseExtraction(profit, opts)
+	if err != nil {
+		val.Errors["profit"] = "The profit cannot be searched, " + err.Error() + "."
+		h.formError(w, "director_input", val)
+		return
+	}
+
+	out := DirectorOutput{
+		Year:       val.Year,
+		Extraction: extraction,
+	}
+
+	h.views.render(w, "director_output", "view", out, h.logger)
+}
+
+func (h Handlers) selfEmployedInputPage(w http.ResponseWriter, r *http.Request) {
+	h.views.render(w, "self_employed_input", "view", h.newTaxInput(), h.logger)
+}
+
+func (h Handlers) selfEmployedOutputPage(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
 	if err != nil {
 		serverError(w, r, err, h.logger)
+		return
+	}
+
+	val := h.newTaxInput()
+
+	turnover, err := money.NewFromString(r.PostForm.Get("turnover"))
+	if err != nil || turnover < 0 {
+		val.Errors["turnover"] = "The value must be a valid positive number."
+	}
+	expenses, err := parseOptionalMoney(r.PostForm.Get("expenses"), 0)
+	if err != nil || expenses < 0 {
+		val.Errors["expenses"] = "The value must be a valid positive number."
+	}
+	income, err := parseOptionalMoney(r.PostForm.Get("income"), 0)
+	if err != nil || income < 0 {
+		val.Errors["income"] = "The value must be a valid positive number."
+	}
+	paidOnAccount, err := parseOptionalMoney(r.PostForm.Get("paid_on_account"), 0)
+	if err != nil || paidOnAccount < 0 {
+		val.Errors["paid_on_account"] = "The value must be a valid positive number."
+	}
+
+	calc, ok := h.parseCalculator(r.PostForm, &val)
+	var opts tax.Options
+	if ok {
+		opts = parseOptions(r.PostForm, calc, val)
+	}
+
+	if len(val.Errors) > 0 {
+		h.formError(w, "self_employed_input", val)
+		return
+	}
+
+	opts.SelfEmployment = tax.SelfEmployment{
+		Turnover:         turnover,
+		Expenses:         expenses,
+		TradingAllowance: r.PostForm.Get("trading_allowance") == "on",
+		VoluntaryClass2:  r.PostForm.Get("voluntary_class2") == "on",
+	}
+
+	sa, err := calc.CalculateSelfAssessment(income, opts, paidOnAccount)
+	if err != nil {
+		serverError(w, r, err, h.logger)
+		return
+	}
+
+	out := SelfEmployedOutput{
+		Year:           val.Year,
+		SelfAssessment: sa,
+	}
+
+	h.views.render(w, "self_employed_output", "view", out, h.logger)
+}
+
+func (h Handlers) contractorInputPage(w http.ResponseWriter, r *http.Request) {
+	h.views.render(w, "contractor_input", "view", h.newTaxInput(), h.logger)
+}
+
+func (h Handlers) contractorOutputPage(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		serverError(w, r, err, h.logger)
+		return
+	}
+
+	val := h.newTaxInput()
+
+	dayRate, err := money.NewFromString(r.PostForm.Get("day_rate"))
+	if err != nil || dayRate <= 0 {
+		val.Errors["day_rate"] = "The value must be a valid positive number."
+	}
+	days, err := strconv.ParseFloat(r.PostForm.Get("days"), 64)
+	if err != nil || days <= 0 || days > 366 {
+		val.Errors["days"] = "The value must be a valid number of days."
+	}
+	margin, err := parseOptionalMoney(r.PostForm.Get("umbrella_margin"), 0)
+	if err != nil || margin < 0 {
+		val.Errors["umbrella_margin"] = "The value must be a valid positive number."
+	}
+	costs, err := parseOptionalMoney(r.PostForm.Get("company_costs"), 0)
+	if err != nil || costs < 0 {
+		val.Errors["company_costs"] = "The value must be a valid positive number."
+	}
+
+	calc, ok := h.parseCalculator(r.PostForm, &val)
+	var opts tax.Options
+	if ok {
+		opts = parseOptions(r.PostForm, calc, val)
+	}
+
+	if len(val.Errors) > 0 {
+		h.formError(w, "contractor_input", val)
+		return
+	}
+
+	contract := tax.Contract{
+		DayRate:        dayRate,
+		Days:           days,
+		UmbrellaMargin: margin,
+		CompanyCosts:   costs,
+	}
+
+	scenarios, err := calc.CompareContract(contract, opts)
+	if err != nil {
+		val.Errors["day_rate"] = "The contract cannot be compared, " + err.Error() + "."
+		h.formError(w, "contractor_input", val)
+		return
+	}
+
+	out := ContractorOutput{
+		Year:      val.Year,
+		Contract:  contract,
+		Scenarios: scenarios,
+	}
+
+	h.views.render(w, "contractor_output", "view", out, h.logger)
+}
+
+func (h Handlers) bonusInputPage(w http.ResponseWriter, r *http.Request) {
+	h.views.render(w, "bonus_input", "view", h.newTaxInput(), h.logger)
+}
+
+func (h Handlers) bonusOutputPage(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		serverError(w, r, err, h.logger)
+		return
+	}
+
+	val := h.newTaxInput()
+
+	salary, err := money.NewFromString(r.PostForm.Get("salary"))
+	if err != nil || salary < 0 {
+		val.Errors["salary"] = "The value must be a valid positive number."
+	}
+	bonus, err := money.NewFromString(r.PostForm.Get("bonus"))
+	if err != nil || bonus <= 0 {
+		val.Errors["bonus"] = "The value must be a valid positive number."
+	}
+	month, err := strconv.Atoi(r.PostForm.Get("month"))
+	if err != nil || month < 1 || month > 12 {
+		val.Errors["month"] = "The value must be a valid tax month."
+	}
+
+	calc, ok := h.parseCalculator(r.PostForm, &val)
+	var opts tax.Options
+	if ok {
+		opts = parseOptions(r.PostForm, calc, val)
+	}
+
+	if len(val.Errors) > 0 {
+		h.formError(w, "bonus_input", val)
+		return
+	}
+
+	breakdown, err := calc.CalculateBonus(salary, bonus, month, opts)
+	if err != nil {
+		val.Errors["bonus"] = "The bonus cannot be calculated, " + err.Error() + "."
+		h.formError(w, "bonus_input", val)
+		return
+	}
+
+	out := BonusOutput{
+		Year:   val.Year,
+		Salary: salary,
+		Bonus:  breakdown,
+	}
+
+	h.views.render(w, "bonus_output", "view", out, h.logger)
+}
+
+// Select the TaxCalculator of the tax year of a date, or of a tax year,
+// the current tax year by default.
+func (h Handlers) parseCalculator(form url.Values, val *TaxInput) (tax.TaxCalculator, bool) {
+	date := form.Get("date")
+	year := form.Get("tax_year")
+
+	switch {
+	case strings.TrimSpace(date) != "":
+		d, err := time.Parse(time.DateOnly, date)
+		if err != nil {
+			val.Errors["tax_year"] = "The date must be a valid date."
+			return tax.TaxCalculator{}, false
+		}
+		val.Year = tax.TaxYearOf(d)
+	case strings.TrimSpace(year) != "":
+		y, err := tax.ParseTaxYear(year)
+		if err != nil {
+			val.Errors["tax_year"] = "The value must be a valid tax year."
+			return tax.TaxCalculator{}, false
+		}
+		val.Year = y
+	}
+
+	calc, err := h.models.years.Calculator(val.Year)
+	if err != nil {
+		val.Errors["tax_year"] = "The rates for the tax year " + val.Year.String() + " are not available."
+		return tax.TaxCalculator{}, false
+	}
+
+	return calc, true
+}
+
+// Parse the options of a calculation shared by the forms and add the
+// validation errors found.
+func parseOptions(form url.Values, calc tax.TaxCalculator, val TaxInput) tax.Options {
+	residency := tax.Residency(form.Get("residency"))
+	if _, ok := calc.IncomeTaxRates[residency]; !ok {
+		val.Errors["residency"] = "The value must be a valid residency."
+	}
+	category := form.Get("category")
+	studentLoan := tax.StudentLoanPlan(form.Get("student_loan"))
+	postgraduateLoan := form.Get("postgraduate_loan") == "on"
+	pensionScheme := tax.PensionScheme(form.Get("pension_scheme"))
+	pensionContribution := form.Get("pension_contribution")
+	pensionUnit := form.Get("pension_unit")
+	code := form.Get("tax_code")
+	marriage := form.Get("marriage")
+
+	savings, err := parseOptionalMoney(form.Get("savings"), 0)
+	if err != nil || savings < 0 {
+		val.Errors["savings"] = "The value must be a valid positive number."
+	}
+
+	dividends, err := parseOptionalMoney(form.Get("dividends"), 0)
+	if err != nil || dividends < 0 {
+		val.Errors["dividends"] = "The value must be a valid positive number."
+	}
+
+	subscriptions, err := parseOptionalMoney(form.Get("subscriptions"), 0)
+	if err != nil || subscriptions < 0 {
+		val.Errors["subscriptions"] = "The value must be a valid positive number."
+	}
+
+	jobExpenses, err := parseOptionalMoney(form.Get("job_expenses"), 0)
+	if err != nil || jobExpenses < 0 {
+		val.Errors["job_expenses"] = "The value must be a valid positive number."
+	}
+
+	giftAid, err := parseOptionalMoney(form.Get("gift_aid"), 0)
+	if err != nil || giftAid < 0 {
+		val.Errors["gift_aid"] = "The value must be a valid positive number."
+	}
+
+	carListPrice, err := parseOptionalMoney(form.Get("car_list_price"), 0)
+	if err != nil || carListPrice < 0 {
+		val.Errors["car_list_price"] = "The value must be a valid positive number."
+	}
+
+	carCO2 := 0
+	if strings.TrimSpace(form.Get("car_co2")) != "" {
+		carCO2, err = strconv.Atoi(form.Get("car_co2"))
+		if err != nil || carCO2 < 0 {
+			val.Errors["car_co2"] = "The value must be a valid number of g/km."
+		}
+	}
+
+	carRange := 0
+	if strings.TrimSpace(form.Get("car_electric_range")) != "" {
+		carRange, err = strconv.Atoi(form.Get("car_electric_range"))
+		if err != nil || carRange < 0 {
+			val.Errors["car_electric_range"] = "The value must be a valid number of miles."
+		}
+	}
+
+	carFuel := tax.FuelType(form.Get("car_fuel"))
+	switch carFuel {
+	case tax.Petrol, tax.Diesel, tax.DieselRDE2, tax.Hybrid, tax.Electric:
+	default:
+		if carListPrice > 0 {
+			val.Errors["car_fuel"] = "The value must be a valid fuel type."
+		}
+	}
+
+	medical, err := parseOptionalMoney(form.Get("medical"), 0)
+	if err != nil || medical < 0 {
+		val.Errors["medical"] = "The value must be a valid positive number."
+	}
+
+	var otherBenefits []tax.Benefit
+	if medical > 0 {
+		otherBenefits = append(otherBenefits, tax.Benefit{Name: "Private Medical", CashEquivalent: medical, Payrolled: form.Get("benefits_payrolled") == "on"})
+	}
+
+	children := 0
+	if strings.TrimSpace(form.Get("children")) != "" {
+		children, err = strconv.Atoi(form.Get("children"))
+		if err != nil || children < 0 {
+			val.Errors["children"] = "The value must be a valid number of children."
+		}
+	}
+
+	if _, ok := calc.NationalInsuranceRates[category]; !ok {
+		val.Errors["category"] = "The value must be a valid National Insurance category letter."
+	}
+
+	// The postgraduate loan has its own switch, and would be repaid twice.
+	if _, ok := calc.StudentLoanRates[studentLoan]; (!ok || studentLoan == tax.Postgraduate) && studentLoan != tax.NoStudentLoan {
+		val.Errors["student_loan"] = "The value must be a valid student loan plan."
+	}
+
+	pension := tax.Pension{Scheme: pensionScheme}
+	switch pensionScheme {
+	case tax.NoPension:
+	case tax.NetPay, tax.ReliefAtSource, tax.SalarySacrifice:
+		if pensionUnit == "%" {
+			rate, err := strconv.ParseFloat(pensionContribution, 64)
+			if err != nil || rate < 0 || rate > 100 {
+				val.Errors["pension_contribution"] = "The value must be a valid percentage."
+			}
+			pension.Rate = rate / 100
+		} else {
+			amount, err := money.NewFromString(pensionContribution)
+			if err != nil || amount < 0 {
+				val.Errors["pension_contribution"] = "The value must be a valid positive number."
+			}
+			pension.Amount = amount
+		}
+	default:
+		val.Errors["pension_scheme"] = "The value must be a valid pension scheme."
+	}
+
+	var marriageAllowance tax.MarriageAllowance
+	marriedCouples := false
+	switch marriage {
+	case "":
+	case "Transferor", "Recipient":
+		marriageAllowance = tax.MarriageAllowance(marriage)
+	case "Married couple's allowance":
+		marriedCouples = true
+	default:
+		val.Errors["marriage"] = "The value must be a valid marriage allowance."
+	}
+
+	var taxCode *taxcode.Code
+	if strings.TrimSpace(code) != "" {
+		c, err := taxcode.Parse(code)
+		if err == nil {
+			err = calc.CheckTaxCode(c)
+		}
+
+		var e *taxcode.Error
+		switch {
+		case errors.As(err, &e):
+			val.Errors["tax_code"] = "The tax code is invalid, " + e.Reason + "."
+		case err != nil:
+			val.Errors["tax_code"] = "The tax code is invalid in " + val.Year.String() + ", " + err.Error() + "."
+		default:
+			taxCode = &c
+		}
+	}
+
+	// The allowances transferred or claimed are part of the tax code.
+	if taxCode != nil && marriage != "" {
+		val.Errors["marriage"] = "The marriage allowances are given by the tax code, leave this empty when a tax code is provided."
+	}
+
+	return tax.Options{
+		Residency:        residency,
+		NICategory:       category,
+		StudentLoan:      studentLoan,
+		PostgraduateLoan: postgraduateLoan,
+		Pension:          pension,
+		Savings:          savings,
+		Dividends:        dividends,
+		TaxCode:          taxCode,
+		ChildBenefit: tax.ChildBenefit{
+			Children: children,
+			Claimed:  form.Get("child_benefit") == "on",
+		},
+		MarriageAllowance:       marriageAllowance,
+		MarriedCouplesAllowance: marriedCouples,
+		BlindPerson:             form.Get("blind_person") == "on",
+		JobExpenses: tax.JobExpenses{
+			Uniform:       form.Get("uniform") == "on",
+			Subscriptions: subscriptions,
+			Other:         jobExpenses,
+		},
+		GiftAid: giftAid,
+		BenefitsInKind: tax.BenefitsInKind{
+			CompanyCar: tax.CompanyCar{
+				ListPrice:     carListPrice,
+				CO2:           carCO2,
+				Fuel:          carFuel,
+				ElectricRange: carRange,
+				FreeFuel:      form.Get("car_free_fuel") == "on",
+				Payrolled:     form.Get("car_payrolled") == "on",
+			},
+			Other: otherBenefits,
+		},
+	}
+}
+
+// Parse a Money from a form value, an empty value being the fallback.
+func parseOptionalMoney(value string, fallback money.Money) (money.Money, error) {
+	if strings.TrimSpace(value) == "" {
+		return fallback, nil
+	}
+
+	return money.NewFromString(value)
+}
+
+// Format a rate as a percentage, e.g. 8.75%.
+func formatPercent(rate float64) string {
+	return strconv.FormatFloat(math.Round(rate*10000)/100, 'f', -1, 64) + "%"
+}
+
+// Parse a float from a form value, an empty value being 0.
+func parseOptionalFloat(value string) (float64, error) {
+	if strings.TrimSpace(value) == "" {
+		return 0, nil
 	}
 
-	h.views.render(w, "tax_output", "view", tax, h.logger)
+	return strconv.ParseFloat(value, 64)
 }
@@ -2,10 +2,15 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
 	"log"
 	"log/slog"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/vfc2/tax-calculator/internal/tax"
 )
@@ -23,23 +28,14 @@ func main() {
 		os.Exit(1)
 	}
 
-	taxConfig, err := loadTaxConfig("./assets/config/income_tax/2024_2025.json")
+	registry, err := loadRegistry("./assets/config")
 	if err != nil {
 		logger.Error("error loading tax rates config", "error", err.Error())
 		os.Exit(1)
 	}
 
-	niConfig, err := loadNIConfig("./assets/config/national_insurance/2024_2025.json")
-	if err != nil {
-		logger.Error("error loading national insurance rates config", "error", err.Error())
-		os.Exit(1)
-	}
-
 	models := Models{
-		calc: tax.TaxCalculator{
-			IncomeTaxRates:         taxConfig,
-			NationalInsuranceRates: niConfig,
-		},
+		years: registry,
 	}
 
 	handlers := &Handlers{
@@ -67,6 +63,15 @@ func routes(h *Handlers, mw *Middlewares) http.Handler {
 	mux.HandleFunc("/", h.home)
 	mux.HandleFunc("GET /inputs", h.inputPage)
 	mux.HandleFunc("POST /calculate", h.outputPage)
+	mux.HandleFunc("GET /rates", h.ratesChart)
+	mux.HandleFunc("GET /director", h.directorInputPage)
+	mux.HandleFunc("POST /director/optimise", h.directorOutputPage)
+	mux.HandleFunc("GET /self-employed", h.selfEmployedInputPage)
+	mux.HandleFunc("POST /self-employed/calculate", h.selfEmployedOutputPage)
+	mux.HandleFunc("GET /contractor", h.contractorInputPage)
+	mux.HandleFunc("POST /contractor/compare", h.contractorOutputPage)
+	mux.HandleFunc("GET /bonus", h.bonusInputPage)
+	mux.HandleFunc("POST /bonus/calculate", h.bonusOutputPage)
 
 	return mw.recovery(mw.logRequest(mw.secureHeaders(mux)))
 }
@@ -76,34 +81,154 @@ func serverError(w http.ResponseWriter, r *http.Request, err error, logger *slog
 	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 }
 
-func loadTaxConfig(filename string) (tax.IncomeTaxRates, error) {
-	tr := &tax.IncomeTaxRates{}
+func clientError(w http.ResponseWriter, status int) {
+	http.Error(w, http.StatusText(status), status)
+}
 
-	f, err := os.ReadFile(filename)
+// Load the rates of every tax year found in the config directory, a tax
+// year being added for each income tax file named as 2024_2025.json.
+func loadRegistry(dir string) (tax.Registry, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "income_tax", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	registry := tax.Registry{}
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		if strings.HasSuffix(name, "_scotland") {
+			continue
+		}
+
+		year, err := tax.ParseTaxYear(name)
+		if err != nil {
+			return nil, err
+		}
+
+		calc, err := loadCalculator(dir, name, year)
+		if err != nil {
+			return nil, fmt.Errorf("tax year %s: %w", year, err)
+		}
+
+		registry[year] = calc
+	}
+
+	if len(registry) == 0 {
+		return nil, fmt.Errorf("no tax year found in %s", dir)
+	}
+
+	return registry, nil
+}
+
+// Load the rates of a tax year from the files named after it.
+func loadCalculator(dir string, name string, year tax.TaxYear) (tax.TaxCalculator, error) {
+	taxConfig, err := loadConfig[tax.IncomeTaxRates](filepath.Join(dir, "income_tax", name+".json"))
+	if err != nil {
+		return tax.TaxCalculator{}, err
+	}
+
+	scottishTaxConfig, err := loadConfig[tax.IncomeTaxRates](filepath.Join(dir, "income_tax", name+"_scotland.json"))
 	if err != nil {
-		return *tr, err
+		return tax.TaxCalculator{}, err
+	}
+
+	niConfig, err := loadConfig[map[string]tax.NationalInsuranceRates](filepath.Join(dir, "national_insurance", name+".json"))
+	if err != nil {
+		return tax.TaxCalculator{}, err
+	}
+
+	// Rates changing within the tax year are optional.
+	niChanges, err := loadConfig[[]tax.NationalInsuranceChange](filepath.Join(dir, "national_insurance", name+"_changes.json"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return tax.TaxCalculator{}, err
 	}
 
-	err = json.Unmarshal(f, tr)
+	niThresholds, err := loadConfig[[]tax.NationalInsuranceThreshold](filepath.Join(dir, "national_insurance", name+"_thresholds.json"))
 	if err != nil {
-		return *tr, err
+		return tax.TaxCalculator{}, err
 	}
 
-	return *tr, nil
+	// Director rates are only published for the years changing the rates.
+	niDirectors, err := loadConfig[map[string]tax.NationalInsuranceRates](filepath.Join(dir, "national_insurance", name+"_directors.json"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return tax.TaxCalculator{}, err
+	}
+
+	studentLoanConfig, err := loadConfig[map[tax.StudentLoanPlan]tax.StudentLoanRates](filepath.Join(dir, "student_loan", name+".json"))
+	if err != nil {
+		return tax.TaxCalculator{}, err
+	}
+
+	selfEmploymentConfig, err := loadConfig[tax.SelfEmploymentRates](filepath.Join(dir, "self_employment", name+".json"))
+	if err != nil {
+		return tax.TaxCalculator{}, err
+	}
+
+	savingsConfig, err := loadConfig[tax.SavingsRates](filepath.Join(dir, "savings", name+".json"))
+	if err != nil {
+		return tax.TaxCalculator{}, err
+	}
+
+	dividendConfig, err := loadConfig[tax.DividendRates](filepath.Join(dir, "dividend", name+".json"))
+	if err != nil {
+		return tax.TaxCalculator{}, err
+	}
+
+	corporationTaxConfig, err := loadConfig[tax.CorporationTaxRates](filepath.Join(dir, "corporation_tax", name+".json"))
+	if err != nil {
+		return tax.TaxCalculator{}, err
+	}
+
+	childBenefitConfig, err := loadConfig[tax.ChildBenefitRates](filepath.Join(dir, "child_benefit", name+".json"))
+	if err != nil {
+		return tax.TaxCalculator{}, err
+	}
+
+	allowanceConfig, err := loadConfig[tax.AllowanceRates](filepath.Join(dir, "allowances", name+".json"))
+	if err != nil {
+		return tax.TaxCalculator{}, err
+	}
+
+	benefitConfig, err := loadConfig[tax.BenefitRates](filepath.Join(dir, "benefits", name+".json"))
+	if err != nil {
+		return tax.TaxCalculator{}, err
+	}
+
+	return tax.TaxCalculator{
+		Year: year,
+		IncomeTaxRates: map[tax.Residency]tax.IncomeTaxRates{
+			tax.RestOfUK: taxConfig,
+			tax.Wales:    taxConfig,
+			tax.Scotland: scottishTaxConfig,
+		},
+		NationalInsuranceRates:         niConfig,
+		NationalInsuranceChanges:       niChanges,
+		NationalInsuranceThresholds:    niThresholds,
+		DirectorNationalInsuranceRates: niDirectors,
+		StudentLoanRates:               studentLoanConfig,
+		SelfEmploymentRates:            selfEmploymentConfig,
+		SavingsRates:                   savingsConfig,
+		DividendRates:                  dividendConfig,
+		CorporationTaxRates:            corporationTaxConfig,
+		ChildBenefitRates:              childBenefitConfig,
+		AllowanceRates:                 allowanceConfig,
+		BenefitRates:                   benefitConfig,
+	}, nil
 }
 
-func loadNIConfig(filename string) (map[string]tax.NationalInsuranceRates, error) {
-	ni := map[string]tax.NationalInsuranceRates{}
+func loadConfig[T any](filename string) (T, error) {
+	var config T
 
 	f, err := os.ReadFile(filename)
 	if err != nil {
-		return ni, err
+		return config, err
 	}
 
-	err = json.Unmarshal(f, &ni)
+	err = json.Unmarshal(f, &config)
 	if err != nil {
-		return ni, err
+		return config, err
 	}
 
-	return ni, nil
+	return config, nil
 }
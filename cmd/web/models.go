@@ -5,5 +5,5 @@ import (
 )
 
 type Models struct {
-	calc tax.TaxCalculator
+	years tax.Registry
 }
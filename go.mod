@@ -2,4 +2,4 @@ module github.com/vfc2/tax-calculator
 
 go 1.22.0
 
-require golang.org/x/text v0.14.0 // indirect
+require golang.org/x/text v0.14.0
@@ -83,6 +83,45 @@ func (m Money) Add(add float64) Money {
 	return m + Money(v)
 }
 
+// Round returns a Money rounded to the specified digits using
+// math.RoundToEven().
+// For example 66495000 with digits = 2 is returned as 66500000.
+func (m Money) Round(digits int) Money {
+	factor := unit / math.Pow10(digits)
+	if factor <= 1 {
+		return m
+	}
+
+	return Money(math.RoundToEven(float64(m)/factor) * factor)
+}
+
+// RoundDown returns a Money rounded down to the specified digits.
+// For example 66498000 with digits = 2 is returned as 66490000.
+func (m Money) RoundDown(digits int) Money {
+	factor := Money(unit / math.Pow10(digits))
+	if factor <= 1 {
+		return m
+	}
+
+	r := m % factor
+	if r < 0 {
+		r += factor
+	}
+
+	return m - r
+}
+
+// RoundUp returns a Money rounded up to the specified digits.
+// For example 66491000 with digits = 2 is returned as 66500000.
+func (m Money) RoundUp(digits int) Money {
+	down := m.RoundDown(digits)
+	if down == m {
+		return m
+	}
+
+	return down + Money(unit/math.Pow10(digits))
+}
+
 // Format returns a Money as a string with the specified digits.
 // For example 66498000 with digits = 2 is returned as 66.50.
 func (m Money) Format(digits int) string {
@@ -90,9 +129,14 @@ func (m Money) Format(digits int) string {
 }
 
 // DisplayCurrency returns a rounded Money as a string with comma
-// thousands separators.
+// thousands separators, the minus of a negative Money going before the
+// sign.
 func (m Money) DisplayCurrency(sign string) string {
 	p := message.NewPrinter(language.English)
 
+	if m < 0 {
+		return p.Sprintf("-%s%.0f", sign, float64(-m)/unit)
+	}
+
 	return p.Sprintf("%s%.0f", sign, float64(m)/unit)
 }
@@ -169,6 +169,65 @@ func TestMoneyAdd(t *testing.T) {
 	}
 }
 
+func TestMoneyRound(t *testing.T) {
+	tests := map[string]struct {
+		base         float64
+		digits       int
+		expected     int64
+		expectedDown int64
+		expectedUp   int64
+	}{
+		"66.498": {
+			base:         66.498,
+			digits:       2,
+			expected:     66500000,
+			expectedDown: 66490000,
+			expectedUp:   66500000,
+		},
+		"66.495": {
+			base:         66.495,
+			digits:       2,
+			expected:     66500000,
+			expectedDown: 66490000,
+			expectedUp:   66500000,
+		},
+		"1951.75": {
+			base:         1951.75,
+			digits:       0,
+			expected:     1952000000,
+			expectedDown: 1951000000,
+			expectedUp:   1952000000,
+		},
+		"3142": {
+			base:         3142,
+			digits:       0,
+			expected:     3142000000,
+			expectedDown: 3142000000,
+			expectedUp:   3142000000,
+		},
+		"-12.345": {
+			base:         -12.345,
+			digits:       2,
+			expected:     -12340000,
+			expectedDown: -12350000,
+			expectedUp:   -12340000,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := New(test.base).Round(test.digits)
+			down := New(test.base).RoundDown(test.digits)
+			up := New(test.base).RoundUp(test.digits)
+
+			if actual != Money(test.expected) || down != Money(test.expectedDown) || up != Money(test.expectedUp) {
+				t.Errorf("got {Round: %v, Down: %v, Up: %v}, want {Round: %v, Down: %v, Up: %v}",
+					actual, down, up, test.expected, test.expectedDown, test.expectedUp)
+			}
+		})
+	}
+}
+
 func TestMoneyFormat(t *testing.T) {
 	tests := map[string]struct {
 		base     string
@@ -209,6 +268,33 @@ func TestMoneyFormat(t *testing.T) {
 	}
 }
 
+func TestMoneyDisplayCurrency(t *testing.T) {
+	tests := map[string]struct {
+		base     string
+		expected string
+	}{
+		"Thousands": {
+			base:     "12570.4",
+			expected: "£12,570",
+		},
+		"Negative": {
+			base:     "-470",
+			expected: "-£470",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			v, _ := NewFromString(test.base)
+			actual := v.DisplayCurrency("£")
+
+			if actual != test.expected {
+				t.Errorf("got %v, want %v", actual, test.expected)
+			}
+		})
+	}
+}
+
 func TestMoneyNewFromString(t *testing.T) {
 	tests := map[string]struct {
 		base     string
@@ -0,0 +1,243 @@
+package tax
+
+import (
+	"fmt"
+	"slices"
+)
+
+// MarriageAllowance is the side of a Marriage Allowance transfer the
+// taxpayer is on.
+type MarriageAllowance string
+
+const (
+	NoMarriageAllowance         MarriageAllowance = ""
+	MarriageAllowanceTransferor MarriageAllowance = "Transferor"
+	MarriageAllowanceRecipient  MarriageAllowance = "Recipient"
+)
+
+// Name of the tax reducer of the Marriage Allowance recipient, and of the
+// Scottish band taxed at the intermediate rate.
+const (
+	marriageAllowanceReducer = "Marriage Allowance"
+	intermediateRateBand     = "Intermediate"
+)
+
+// AllowanceRates holds the yearly amounts of the allowances claimed on top
+// of the personal allowance, and the flat rate expense for uniforms.
+type AllowanceRates struct {
+	MarriageAllowance       MarriageAllowanceRates
+	MarriedCouplesAllowance MarriedCouplesAllowanceRates
+	BlindPersonsAllowance   Money
+	UniformFlatRate         Money
+}
+
+// JobExpenses are the yearly expenses of an employment added to the
+// allowance through the tax code, the flat rate expense being claimed for
+// uniforms and professional subscriptions being paid to approved bodies.
+type JobExpenses struct {
+	Uniform       bool
+	Subscriptions Money
+	Other         Money
+}
+
+// AllowanceAdjustment is a step of the build-up of the allowance, adding
+// to or taking from it.
+type AllowanceAdjustment struct {
+	Name   string
+	Amount Money
+}
+
+// allowanceStep returns the adjustment of the allowance built up so far.
+type allowanceStep func(allowance Money) AllowanceAdjustment
+
+// MarriageAllowanceRates holds the part of the personal allowance a
+// spouse or civil partner can transfer, and the rate at which it reduces
+// the tax of the recipient.
+type MarriageAllowanceRates struct {
+	Transfer Money
+	Rate     float64
+}
+
+// MarriedCouplesAllowanceRates holds the Married Couple's Allowance, the
+// adjusted net income above which it is reduced by £1 for every £2 down
+// to its minimum, and the rate at which it reduces the tax.
+type MarriedCouplesAllowanceRates struct {
+	Maximum     Money
+	Minimum     Money
+	IncomeLimit Money
+	Rate        float64
+}
+
+// Calculate the personal allowance transferred to a spouse or civil
+// partner and the tax reducers of the allowances claimed, each with the
+// allowance and its rate, before they are limited to the tax due.
+// Requirements from https://www.gov.uk/marriage-allowance
+// and https://www.gov.uk/married-couples-allowance
+func (r AllowanceRates) calculateMarriage(adjustedNetIncome Money, opts Options) (Money, []BandBreakdown, error) {
+	if opts.MarriageAllowance != NoMarriageAllowance && opts.MarriedCouplesAllowance {
+		return 0, nil, fmt.Errorf("the marriage allowance cannot be claimed with the married couple's allowance")
+	}
+	if opts.MarriageAllowance != NoMarriageAllowance && r.MarriageAllowance.Transfer == 0 {
+		return 0, nil, fmt.Errorf("the marriage allowance rates are not available")
+	}
+	if opts.MarriedCouplesAllowance && r.MarriedCouplesAllowance.Maximum == 0 {
+		return 0, nil, fmt.Errorf("the married couple's allowance rates are not available")
+	}
+
+	ma, mca := r.MarriageAllowance, r.MarriedCouplesAllowance
+
+	switch opts.MarriageAllowance {
+	case NoMarriageAllowance:
+	case MarriageAllowanceTransferor:
+		return ma.Transfer, nil, nil
+	case MarriageAllowanceRecipient:
+		return 0, []BandBreakdown{{Name: marriageAllowanceReducer, Rate: ma.Rate, Amount: ma.Transfer, Tax: ma.Transfer.Mul(ma.Rate)}}, nil
+	default:
+		return 0, nil, fmt.Errorf("the requested %s marriage allowance does not exist", opts.MarriageAllowance)
+	}
+
+	if opts.MarriedCouplesAllowance {
+		over := max((adjustedNetIncome - mca.IncomeLimit).Mul(0.5), 0)
+		allowance := max(mca.Maximum-over, mca.Minimum)
+
+		return 0, []BandBreakdown{{Name: "Married Couple's Allowance", Rate: mca.Rate, Amount: allowance, Tax: allowance.Mul(mca.Rate)}}, nil
+	}
+
+	return 0, nil, nil
+}
+
+// Calculate the yearly job expenses claimed.
+// Requirements from https://www.gov.uk/tax-relief-for-employees
+func (r AllowanceRates) calculateJobExpenses(e JobExpenses) Money {
+	total := e.Subscriptions + e.Other
+	if e.Uniform {
+		total += r.UniformFlatRate
+	}
+
+	return total
+}
+
+// Build the allowance from the personal allowance, its taper and the
+// allowance transferred, then the Blind Person's Allowance and the job
+// expenses, and return it with the adjustments of each step.
+// Requirements from https://www.gov.uk/blind-persons-allowance
+// and https://www.gov.uk/tax-relief-for-employees
+func (t TaxCalculator) buildAllowance(rates IncomeTaxRates, adjustedNetIncome Money, transferred Money, opts Options) (Money, []AllowanceAdjustment, error) {
+	if opts.BlindPerson && t.AllowanceRates.BlindPersonsAllowance == 0 {
+		return 0, nil, fmt.Errorf("the blind person's allowance rates are not available")
+	}
+	if opts.JobExpenses.Uniform && t.AllowanceRates.UniformFlatRate == 0 {
+		return 0, nil, fmt.Errorf("the uniform flat rate expense is not available")
+	}
+	if opts.JobExpenses.Subscriptions < 0 || opts.JobExpenses.Other < 0 {
+		return 0, nil, fmt.Errorf("the job expenses cannot be negative")
+	}
+
+	steps := []allowanceStep{
+		func(Money) AllowanceAdjustment {
+			return AllowanceAdjustment{Name: "Personal Allowance", Amount: rates.PersonalAllowance}
+		},
+		func(Money) AllowanceAdjustment {
+			return AllowanceAdjustment{Name: "Personal Allowance Taper", Amount: rates.calculateTaxAllowance(adjustedNetIncome) - rates.PersonalAllowance}
+		},
+		func(allowance Money) AllowanceAdjustment {
+			return AllowanceAdjustment{Name: "Marriage Allowance Transferred", Amount: -min(transferred, allowance)}
+		},
+		func(Money) AllowanceAdjustment {
+			var amount Money
+			if opts.BlindPerson {
+				amount = t.AllowanceRates.BlindPersonsAllowance
+			}
+			return AllowanceAdjustment{Name: "Blind Person's Allowance", Amount: amount}
+		},
+		func(Money) AllowanceAdjustment {
+			var amount Money
+			if opts.JobExpenses.Uniform {
+				amount = t.AllowanceRates.UniformFlatRate
+			}
+			return AllowanceAdjustment{Name: "Uniform Flat Rate Expense", Amount: amount}
+		},
+		func(Money) AllowanceAdjustment {
+			return AllowanceAdjustment{Name: "Professional Subscriptions", Amount: opts.JobExpenses.Subscriptions}
+		},
+		func(Money) AllowanceAdjustment {
+			return AllowanceAdjustment{Name: "Job Expenses", Amount: opts.JobExpenses.Other}
+		},
+	}
+
+	allowance, adjustments := applyAllowanceSteps(steps)
+
+	return allowance, adjustments, nil
+}
+
+// Apply the steps of an allowance in order, from 0, and return the
+// allowance and the adjustments made. Steps not adjusting the allowance
+// are left out, except the first one.
+func applyAllowanceSteps(steps []allowanceStep) (Money, []AllowanceAdjustment) {
+	var allowance Money
+	var adjustments []AllowanceAdjustment
+
+	for i, step := range steps {
+		a := step(allowance)
+		a.Amount = max(allowance+a.Amount, 0) - allowance
+		if a.Amount == 0 && i > 0 {
+			continue
+		}
+
+		allowance += a.Amount
+		adjustments = append(adjustments, a)
+	}
+
+	return allowance, adjustments
+}
+
+// Limit the tax reducers, in order, to the reduction of the tax given.
+func limitReducers(reducers []BandBreakdown, reduction Money) []BandBreakdown {
+	limited := make([]BandBreakdown, len(reducers))
+
+	for i, r := range reducers {
+		r.Tax = min(r.Tax, reduction)
+		reduction -= r.Tax
+		limited[i] = r
+	}
+
+	return limited
+}
+
+// Whether income, savings or dividends are taxed above the basic rate, the
+// starter and intermediate rates of Scotland included, the recipient of
+// the Marriage Allowance being then not eligible.
+// Requirements from https://www.gov.uk/marriage-allowance
+func taxedAboveBasicRate(tax IncomeTaxBreakdown) bool {
+	return taxedAbove(tax.Bands, basicRateBand, intermediateRateBand) ||
+		taxedAbove(tax.SavingsBands, basicRateBand) ||
+		taxedAbove(tax.DividendBands, ordinaryRateBand)
+}
+
+// Whether an amount is taxed in a band after the last of the named ones.
+func taxedAbove(bands []BandBreakdown, names ...string) bool {
+	last := -1
+	for i, b := range bands {
+		if slices.Contains(names, b.Name) {
+			last = i
+		}
+	}
+
+	for _, b := range bands[last+1:] {
+		if b.Amount > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Total of the tax reducers.
+func totalReducers(reducers []BandBreakdown) Money {
+	var total Money
+	for _, r := range reducers {
+		total += r.Tax
+	}
+
+	return total
+}
@@ -0,0 +1,333 @@
+package tax
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+	"github.com/vfc2/tax-calculator/internal/taxcode"
+)
+
+var allowanceRates = AllowanceRates{
+	MarriageAllowance: MarriageAllowanceRates{
+		Transfer: money.New(1260),
+		Rate:     0.2,
+	},
+	MarriedCouplesAllowance: MarriedCouplesAllowanceRates{
+		Maximum:     money.New(11080),
+		Minimum:     money.New(4280),
+		IncomeLimit: money.New(37000),
+		Rate:        0.1,
+	},
+	BlindPersonsAllowance: money.New(3070),
+	UniformFlatRate:       money.New(60),
+}
+
+func TestBuildAllowance(t *testing.T) {
+	tax := TaxCalculator{AllowanceRates: allowanceRates}
+
+	tests := map[string]struct {
+		adjustedNetIncome Money
+		transferred       Money
+		opts              Options
+		expected          Money
+		adjustments       []AllowanceAdjustment
+	}{
+		"PersonalAllowance": {
+			adjustedNetIncome: money.New(30000),
+			expected:          money.New(12570),
+			adjustments:       []AllowanceAdjustment{{Name: "Personal Allowance", Amount: money.New(12570)}},
+		},
+		"Taper": {
+			adjustedNetIncome: money.New(110000),
+			expected:          money.New(7570),
+			adjustments: []AllowanceAdjustment{
+				{Name: "Personal Allowance", Amount: money.New(12570)},
+				{Name: "Personal Allowance Taper", Amount: money.New(-5000)},
+			},
+		},
+		"Transferred": {
+			adjustedNetIncome: money.New(10000),
+			transferred:       money.New(1260),
+			expected:          money.New(11310),
+			adjustments: []AllowanceAdjustment{
+				{Name: "Personal Allowance", Amount: money.New(12570)},
+				{Name: "Marriage Allowance Transferred", Amount: money.New(-1260)},
+			},
+		},
+		// The Blind Person's Allowance and the job expenses are added after
+		// the personal allowance is fully withdrawn.
+		"BlindPersonAndJobExpenses": {
+			adjustedNetIncome: money.New(150000),
+			opts: Options{
+				BlindPerson: true,
+				JobExpenses: JobExpenses{Uniform: true, Subscriptions: money.New(200), Other: money.New(150)},
+			},
+			expected: money.New(3480),
+			adjustments: []AllowanceAdjustment{
+				{Name: "Personal Allowance", Amount: money.New(12570)},
+				{Name: "Personal Allowance Taper", Amount: money.New(-12570)},
+				{Name: "Blind Person's Allowance", Amount: money.New(3070)},
+				{Name: "Uniform Flat Rate Expense", Amount: money.New(60)},
+				{Name: "Professional Subscriptions", Amount: money.New(200)},
+				{Name: "Job Expenses", Amount: money.New(150)},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, adjustments, err := tax.buildAllowance(taxRates, test.adjustedNetIncome, test.transferred, test.opts)
+			if err != nil {
+				t.Fatalf("an unexpected error was returned: %v", err)
+			}
+
+			if actual != test.expected || !reflect.DeepEqual(adjustments, test.adjustments) {
+				t.Errorf("got {Allowance: %v, Adjustments: %v}, want {Allowance: %v, Adjustments: %v}", actual, adjustments, test.expected, test.adjustments)
+			}
+		})
+	}
+
+	tests_fail := map[string]struct {
+		rates AllowanceRates
+		opts  Options
+	}{
+		"NoBlindPersonsAllowance": {
+			rates: AllowanceRates{},
+			opts:  Options{BlindPerson: true},
+		},
+		"NoUniformFlatRate": {
+			rates: AllowanceRates{},
+			opts:  Options{JobExpenses: JobExpenses{Uniform: true}},
+		},
+		"NegativeExpenses": {
+			rates: allowanceRates,
+			opts:  Options{JobExpenses: JobExpenses{Other: money.New(-100)}},
+		},
+	}
+
+	for name, test := range tests_fail {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := TaxCalculator{AllowanceRates: test.rates}.buildAllowance(taxRates, money.New(30000), 0, test.opts)
+			if err == nil {
+				t.Error("an error was expected but not returned")
+			}
+		})
+	}
+}
+
+func TestMarriage(t *testing.T) {
+	tests := map[string]struct {
+		adjustedNetIncome Money
+		opts              Options
+		transferred       Money
+		reducers          []BandBreakdown
+	}{
+		"None": {
+			adjustedNetIncome: money.New(30000),
+			opts:              Options{},
+		},
+		"Transferor": {
+			adjustedNetIncome: money.New(10000),
+			opts:              Options{MarriageAllowance: MarriageAllowanceTransferor},
+			transferred:       money.New(1260),
+		},
+		"Recipient": {
+			adjustedNetIncome: money.New(30000),
+			opts:              Options{MarriageAllowance: MarriageAllowanceRecipient},
+			reducers:          []BandBreakdown{{Name: "Marriage Allowance", Rate: 0.2, Amount: money.New(1260), Tax: money.New(252)}},
+		},
+		"MarriedCouplesAllowance": {
+			adjustedNetIncome: money.New(30000),
+			opts:              Options{MarriedCouplesAllowance: true},
+			reducers:          []BandBreakdown{{Name: "Married Couple's Allowance", Rate: 0.1, Amount: money.New(11080), Tax: money.New(1108)}},
+		},
+		"MarriedCouplesAllowanceTapered": {
+			adjustedNetIncome: money.New(41000),
+			opts:              Options{MarriedCouplesAllowance: true},
+			reducers:          []BandBreakdown{{Name: "Married Couple's Allowance", Rate: 0.1, Amount: money.New(9080), Tax: money.New(908)}},
+		},
+		"MarriedCouplesAllowanceMinimum": {
+			adjustedNetIncome: money.New(60000),
+			opts:              Options{MarriedCouplesAllowance: true},
+			reducers:          []BandBreakdown{{Name: "Married Couple's Allowance", Rate: 0.1, Amount: money.New(4280), Tax: money.New(428)}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			transferred, reducers, err := allowanceRates.calculateMarriage(test.adjustedNetIncome, test.opts)
+			if err != nil {
+				t.Fatalf("an unexpected error was returned: %v", err)
+			}
+
+			if transferred != test.transferred || !reflect.DeepEqual(reducers, test.reducers) {
+				t.Errorf("got {Transferred: %v, Reducers: %v}, want {Transferred: %v, Reducers: %v}", transferred, reducers, test.transferred, test.reducers)
+			}
+		})
+	}
+
+	tests_fail := map[string]struct {
+		rates AllowanceRates
+		opts  Options
+	}{
+		"Both": {
+			rates: allowanceRates,
+			opts:  Options{MarriageAllowance: MarriageAllowanceRecipient, MarriedCouplesAllowance: true},
+		},
+		"UnknownMarriageAllowance": {
+			rates: allowanceRates,
+			opts:  Options{MarriageAllowance: "Partner"},
+		},
+		"NoMarriageAllowanceRates": {
+			rates: AllowanceRates{},
+			opts:  Options{MarriageAllowance: MarriageAllowanceTransferor},
+		},
+		"NoMarriedCouplesAllowanceRates": {
+			rates: AllowanceRates{},
+			opts:  Options{MarriedCouplesAllowance: true},
+		},
+	}
+
+	for name, test := range tests_fail {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := test.rates.calculateMarriage(money.New(30000), test.opts)
+			if err == nil {
+				t.Error("an error was expected but not returned")
+			}
+		})
+	}
+}
+
+func TestTakeHomeAllowances(t *testing.T) {
+	tax := TaxCalculator{
+		IncomeTaxRates:         map[Residency]IncomeTaxRates{RestOfUK: taxRates, Scotland: scottishTaxRates},
+		NationalInsuranceRates: niRates,
+		DividendRates:          dividendRates,
+		AllowanceRates:         allowanceRates,
+	}
+
+	tests := map[string]struct {
+		income     Money
+		opts       Options
+		allowance  Money
+		reduction  Money
+		taxed      Money
+		ineligible bool
+	}{
+		"Transferor": {
+			income:    money.New(12000),
+			opts:      Options{Residency: RestOfUK, NICategory: "A", MarriageAllowance: MarriageAllowanceTransferor},
+			allowance: money.New(11310),
+			taxed:     money.New(138),
+		},
+		// The transferor is not eligible with an income above the personal
+		// allowance.
+		"TransferorAboveAllowance": {
+			income:     money.New(20000),
+			opts:       Options{Residency: RestOfUK, NICategory: "A", MarriageAllowance: MarriageAllowanceTransferor},
+			allowance:  money.New(12570),
+			taxed:      money.New(1486),
+			ineligible: true,
+		},
+		"Recipient": {
+			income:    money.New(30000),
+			opts:      Options{Residency: RestOfUK, NICategory: "A", MarriageAllowance: MarriageAllowanceRecipient},
+			allowance: money.New(12570),
+			reduction: money.New(252),
+			taxed:     money.New(3234),
+		},
+		// The reducer left over by the income tax reduces the tax on the
+		// dividends above the dividend allowance.
+		"RecipientDividends": {
+			income:    money.New(12570),
+			opts:      Options{Residency: RestOfUK, NICategory: "A", MarriageAllowance: MarriageAllowanceRecipient, Dividends: money.New(5000)},
+			allowance: money.New(12570),
+			reduction: money.New(252),
+			taxed:     money.New(141.75),
+		},
+		// The recipient is not eligible once taxed above the basic rate.
+		"RecipientHigherRate": {
+			income:     money.New(80000),
+			opts:       Options{Residency: RestOfUK, NICategory: "A", MarriageAllowance: MarriageAllowanceRecipient},
+			allowance:  money.New(12570),
+			taxed:      money.New(19432),
+			ineligible: true,
+		},
+		"RecipientAdditionalRate": {
+			income:     money.New(200000),
+			opts:       Options{Residency: RestOfUK, NICategory: "A", MarriageAllowance: MarriageAllowanceRecipient},
+			allowance:  0,
+			taxed:      money.New(76203),
+			ineligible: true,
+		},
+		// In Scotland, the intermediate rate is eligible, the higher rate
+		// is not.
+		"RecipientScottishIntermediateRate": {
+			income:    money.New(40000),
+			opts:      Options{Residency: Scotland, NICategory: "A", MarriageAllowance: MarriageAllowanceRecipient},
+			allowance: money.New(12570),
+			reduction: money.New(252),
+			taxed:     money.New(5345.33),
+		},
+		"RecipientScottishHigherRate": {
+			income:     money.New(50000),
+			opts:       Options{Residency: Scotland, NICategory: "A", MarriageAllowance: MarriageAllowanceRecipient},
+			allowance:  money.New(12570),
+			taxed:      money.New(9028.31),
+			ineligible: true,
+		},
+		"MarriedCouplesAllowance": {
+			income:    money.New(20000),
+			opts:      Options{Residency: RestOfUK, NICategory: "A", MarriedCouplesAllowance: true},
+			allowance: money.New(12570),
+			reduction: money.New(1108),
+			taxed:     money.New(378),
+		},
+		// The job expenses reduce the adjusted net income, restoring part
+		// of the tapered personal allowance.
+		"BlindPersonAndJobExpenses": {
+			income:    money.New(102000),
+			opts:      Options{Residency: RestOfUK, NICategory: "A", BlindPerson: true, JobExpenses: JobExpenses{Other: money.New(1000)}},
+			allowance: money.New(16140),
+			taxed:     money.New(26804),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, err := tax.CalculateTakeHome(test.income, test.opts)
+			if err != nil {
+				t.Fatalf("an unexpected error was returned: %v", err)
+			}
+
+			if actual.Allowance != test.allowance || actual.TaxReduction != test.reduction || actual.Taxed != test.taxed {
+				t.Errorf("got {Allowance: %v, TaxReduction: %v, Taxed: %v}, want {Allowance: %v, TaxReduction: %v, Taxed: %v}",
+					actual.Allowance, actual.TaxReduction, actual.Taxed, test.allowance, test.reduction, test.taxed)
+			}
+
+			if actual.MarriageAllowanceIneligible != test.ineligible {
+				t.Errorf("got MarriageAllowanceIneligible %v, want %v", actual.MarriageAllowanceIneligible, test.ineligible)
+			}
+		})
+	}
+	tests_fail := map[string]struct {
+		opts Options
+	}{
+		"MarriageAllowanceWithTaxCode": {
+			opts: Options{Residency: RestOfUK, NICategory: "A", MarriageAllowance: MarriageAllowanceRecipient, TaxCode: &taxcode.Code{Kind: taxcode.Allowance, Number: 1383, Letter: "M"}},
+		},
+		"MarriedCouplesAllowanceWithTaxCode": {
+			opts: Options{Residency: RestOfUK, NICategory: "A", MarriedCouplesAllowance: true, TaxCode: &taxcode.Code{Kind: taxcode.Allowance, Number: 1257, Letter: "L"}},
+		},
+	}
+
+	for name, test := range tests_fail {
+		t.Run(name, func(t *testing.T) {
+			_, err := tax.CalculateTakeHome(money.New(30000), test.opts)
+			if err == nil {
+				t.Error("an error was expected but not returned")
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package tax
+
+import (
+	"fmt"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+)
+
+// Highest capital contribution to a company car deducted from its list
+// price.
+var maxCapitalContribution = money.New(5000)
+
+// FuelType is the fuel of a company car, diesel cars not meeting the RDE2
+// standard paying a supplement.
+type FuelType string
+
+const (
+	Petrol     FuelType = "Petrol"
+	Diesel     FuelType = "Diesel"
+	DieselRDE2 FuelType = "Diesel RDE2"
+	Hybrid     FuelType = "Hybrid"
+	Electric   FuelType = "Electric"
+)
+
+// BenefitRates holds the company car rates and the rate of Class 1A
+// National Insurance paid by the employer on benefits in kind.
+type BenefitRates struct {
+	CompanyCar  CompanyCarRates
+	Class1ARate float64
+}
+
+// CompanyCarRates holds the appropriate percentages of the list price of a
+// company car taxed as a benefit: zero emission cars have their own rate,
+// cars emitting up to the low emission limit are banded on their electric
+// range and the others on their CO2 emissions, up to the maximum rate
+// once the diesel supplement is added. The fuel benefit is the same
+// percentage of the fuel benefit charge.
+type CompanyCarRates struct {
+	ZeroEmissionRate  float64
+	LowEmissionLimit  int
+	ElectricRanges    []ElectricRangeBand
+	Emissions         []EmissionBand
+	DieselSupplement  float64
+	MaximumRate       float64
+	FuelBenefitCharge Money
+}
+
+// ElectricRangeBand is the appropriate percentage of low emission cars
+// from an electric range in miles.
+type ElectricRangeBand struct {
+	MinRange int
+	Rate     float64
+}
+
+// EmissionBand is the appropriate percentage of cars from an emission of
+// CO2 in g/km.
+type EmissionBand struct {
+	MinCO2 int
+	Rate   float64
+}
+
+// CompanyCar describes a car made available for private use, the capital
+// contribution paid towards its price and the yearly contribution paid
+// for its private use. The fuel is free when the employer pays for
+// private mileage.
+type CompanyCar struct {
+	ListPrice              Money
+	CO2                    int
+	Fuel                   FuelType
+	ElectricRange          int
+	FreeFuel               bool
+	CapitalContribution    Money
+	PrivateUseContribution Money
+	Payrolled              bool
+}
+
+// Benefit is a benefit in kind with its yearly cash equivalent, e.g.
+// private medical insurance. Payrolled benefits are taxed through the pay,
+// the others through the tax code.
+type Benefit struct {
+	Name           string
+	CashEquivalent Money
+	Payrolled      bool
+}
+
+// BenefitsInKind are the benefits provided by the employer, a company car
+// with a list price of 0 meaning no car.
+type BenefitsInKind struct {
+	CompanyCar CompanyCar
+	Other      []Benefit
+}
+
+// Find the appropriate percentage of a company car.
+// Requirements from https://www.gov.uk/government/publications/rates-and-allowances-company-car-benefit
+func (r CompanyCarRates) appropriatePercentage(car CompanyCar) (float64, error) {
+	if car.CO2 < 0 || car.ElectricRange < 0 {
+		return 0, fmt.Errorf("the CO2 emissions and electric range of the company car cannot be negative")
+	}
+
+	co2 := car.CO2
+	switch car.Fuel {
+	case Electric:
+		co2 = 0
+	case Petrol, Diesel, DieselRDE2, Hybrid:
+	default:
+		return 0, fmt.Errorf("the requested %s fuel type does not exist", car.Fuel)
+	}
+
+	var rate float64
+	switch {
+	case co2 == 0:
+		rate = r.ZeroEmissionRate
+	case co2 <= r.LowEmissionLimit:
+		for _, b := range r.ElectricRanges {
+			if car.ElectricRange >= b.MinRange {
+				rate = b.Rate
+				break
+			}
+		}
+	default:
+		for _, b := range r.Emissions {
+			if co2 >= b.MinCO2 {
+				rate = b.Rate
+			}
+		}
+	}
+
+	if car.Fuel == Diesel {
+		rate += r.DieselSupplement
+	}
+
+	return min(rate, r.MaximumRate), nil
+}
+
+// Calculate the yearly cash equivalents of a company car and of its free
+// fuel, rounded down to the pound.
+// Requirements from https://www.gov.uk/calculate-tax-on-company-cars
+func (r CompanyCarRates) calculateCarBenefit(car CompanyCar) (Money, Money, error) {
+	if car.ListPrice <= 0 {
+		return 0, 0, nil
+	}
+
+	rate, err := r.appropriatePercentage(car)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	price := car.ListPrice - min(car.CapitalContribution, maxCapitalContribution)
+	benefit := max(price.Mul(rate)-car.PrivateUseContribution, 0).RoundDown(0)
+
+	var fuel Money
+	if car.FreeFuel && car.Fuel != Electric {
+		fuel = r.FuelBenefitCharge.Mul(rate).RoundDown(0)
+	}
+
+	return benefit, fuel, nil
+}
+
+// Calculate the cash equivalent of each benefit in kind.
+func (r BenefitRates) calculateBenefits(b BenefitsInKind) ([]Benefit, error) {
+	var benefits []Benefit
+
+	if b.CompanyCar.ListPrice > 0 && r.CompanyCar.MaximumRate == 0 {
+		return nil, fmt.Errorf("the company car rates are not available")
+	}
+
+	car, fuel, err := r.CompanyCar.calculateCarBenefit(b.CompanyCar)
+	if err != nil {
+		return nil, err
+	}
+	if car > 0 {
+		benefits = append(benefits, Benefit{Name: "Company Car", CashEquivalent: car, Payrolled: b.CompanyCar.Payrolled})
+	}
+	if fuel > 0 {
+		benefits = append(benefits, Benefit{Name: "Car Fuel", CashEquivalent: fuel, Payrolled: b.CompanyCar.Payrolled})
+	}
+
+	for _, o := range b.Other {
+		if o.CashEquivalent < 0 {
+			return nil, fmt.Errorf("the cash equivalent of %s cannot be negative", o.Name)
+		}
+		if o.CashEquivalent > 0 {
+			benefits = append(benefits, o)
+		}
+	}
+
+	return benefits, nil
+}
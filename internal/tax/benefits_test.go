@@ -0,0 +1,202 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+	"github.com/vfc2/tax-calculator/internal/taxcode"
+)
+
+var benefitRates = BenefitRates{
+	CompanyCar: CompanyCarRates{
+		ZeroEmissionRate: 0.02,
+		LowEmissionLimit: 50,
+		ElectricRanges: []ElectricRangeBand{
+			{MinRange: 130, Rate: 0.02},
+			{MinRange: 70, Rate: 0.05},
+			{MinRange: 40, Rate: 0.08},
+			{MinRange: 30, Rate: 0.12},
+			{MinRange: 0, Rate: 0.14},
+		},
+		Emissions: []EmissionBand{
+			{MinCO2: 51, Rate: 0.15},
+			{MinCO2: 55, Rate: 0.16},
+			{MinCO2: 60, Rate: 0.17},
+			{MinCO2: 100, Rate: 0.25},
+			{MinCO2: 160, Rate: 0.37},
+		},
+		DieselSupplement:  0.04,
+		MaximumRate:       0.37,
+		FuelBenefitCharge: money.New(27800),
+	},
+	Class1ARate: 0.138,
+}
+
+func TestAppropriatePercentage(t *testing.T) {
+	tests := map[string]struct {
+		car      CompanyCar
+		expected float64
+	}{
+		"Electric": {
+			car:      CompanyCar{Fuel: Electric, CO2: 10},
+			expected: 0.02,
+		},
+		"HybridElectricRange": {
+			car:      CompanyCar{Fuel: Hybrid, CO2: 30, ElectricRange: 45},
+			expected: 0.08,
+		},
+		"HybridShortRange": {
+			car:      CompanyCar{Fuel: Hybrid, CO2: 45, ElectricRange: 20},
+			expected: 0.14,
+		},
+		"Petrol": {
+			car:      CompanyCar{Fuel: Petrol, CO2: 57},
+			expected: 0.16,
+		},
+		"DieselSupplement": {
+			car:      CompanyCar{Fuel: Diesel, CO2: 100},
+			expected: 0.29,
+		},
+		"DieselRDE2": {
+			car:      CompanyCar{Fuel: DieselRDE2, CO2: 100},
+			expected: 0.25,
+		},
+		"Maximum": {
+			car:      CompanyCar{Fuel: Diesel, CO2: 180},
+			expected: 0.37,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, err := benefitRates.CompanyCar.appropriatePercentage(test.car)
+			if err != nil {
+				t.Fatalf("an unexpected error was returned: %v", err)
+			}
+
+			if actual != test.expected {
+				t.Errorf("got %v, want %v", actual, test.expected)
+			}
+		})
+	}
+
+	tests_fail := map[string]struct {
+		car CompanyCar
+	}{
+		"NegativeCO2": {
+			car: CompanyCar{Fuel: Petrol, CO2: -1},
+		},
+		"UnknownFuel": {
+			car: CompanyCar{Fuel: "Hydrogen", CO2: 0},
+		},
+	}
+
+	for name, test := range tests_fail {
+		t.Run(name, func(t *testing.T) {
+			_, err := benefitRates.CompanyCar.appropriatePercentage(test.car)
+			if err == nil {
+				t.Error("an error was expected but not returned")
+			}
+		})
+	}
+}
+
+func TestCarBenefit(t *testing.T) {
+	tests := map[string]struct {
+		car          CompanyCar
+		expectedCar  Money
+		expectedFuel Money
+	}{
+		"NoCar": {
+			car: CompanyCar{},
+		},
+		"Car": {
+			car:         CompanyCar{ListPrice: money.New(40000), Fuel: Petrol, CO2: 100},
+			expectedCar: money.New(10000),
+		},
+		// The capital contribution is limited to 5,000.
+		"Contributions": {
+			car:         CompanyCar{ListPrice: money.New(40000), Fuel: Petrol, CO2: 100, CapitalContribution: money.New(6000), PrivateUseContribution: money.New(1000)},
+			expectedCar: money.New(7750),
+		},
+		"FreeFuel": {
+			car:          CompanyCar{ListPrice: money.New(40000), Fuel: Petrol, CO2: 100, FreeFuel: true},
+			expectedCar:  money.New(10000),
+			expectedFuel: money.New(6950),
+		},
+		"ElectricNoFuelBenefit": {
+			car:         CompanyCar{ListPrice: money.New(45123), Fuel: Electric, FreeFuel: true},
+			expectedCar: money.New(902),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			car, fuel, err := benefitRates.CompanyCar.calculateCarBenefit(test.car)
+			if err != nil {
+				t.Fatalf("an unexpected error was returned: %v", err)
+			}
+
+			if car != test.expectedCar || fuel != test.expectedFuel {
+				t.Errorf("got {Car: %v, Fuel: %v}, want {Car: %v, Fuel: %v}", car, fuel, test.expectedCar, test.expectedFuel)
+			}
+		})
+	}
+}
+
+func TestTakeHomeBenefits(t *testing.T) {
+	tax := TaxCalculator{
+		IncomeTaxRates:         map[Residency]IncomeTaxRates{RestOfUK: taxRates},
+		NationalInsuranceRates: niRates,
+		BenefitRates:           benefitRates,
+	}
+	benefits := BenefitsInKind{
+		CompanyCar: CompanyCar{ListPrice: money.New(40000), Fuel: Petrol, CO2: 100},
+		Other:      []Benefit{{Name: "Private Medical", CashEquivalent: money.New(1200), Payrolled: true}},
+	}
+	code, _ := taxcode.Parse("1257L")
+
+	tests := map[string]struct {
+		opts     Options
+		expected Money
+	}{
+		"AllTaxed": {
+			opts:     Options{Residency: RestOfUK, NICategory: "A", BenefitsInKind: benefits},
+			expected: money.New(11912),
+		},
+		// The company car is taxed through the tax code, only the payrolled
+		// medical cover being added to the pay, with an allowance of 12,579.
+		"TaxCode": {
+			opts:     Options{Residency: RestOfUK, NICategory: "A", BenefitsInKind: benefits, TaxCode: &code},
+			expected: money.New(7908.40),
+		},
+	}
+
+	without, _ := tax.CalculateTakeHome(money.New(50000), Options{Residency: RestOfUK, NICategory: "A"})
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, err := tax.CalculateTakeHome(money.New(50000), test.opts)
+			if err != nil {
+				t.Fatalf("an unexpected error was returned: %v", err)
+			}
+
+			if actual.Taxed != test.expected || actual.BenefitsInKind != money.New(11200) || actual.AdjustedNetIncome != money.New(61200) {
+				t.Errorf("got {Taxed: %v, BenefitsInKind: %v, AdjustedNetIncome: %v}, want {Taxed: %v, BenefitsInKind: %v, AdjustedNetIncome: %v}",
+					actual.Taxed, actual.BenefitsInKind, actual.AdjustedNetIncome, test.expected, money.New(11200), money.New(61200))
+			}
+
+			// No employee National Insurance on benefits, Class 1A at 13.8%
+			// paid by the employer.
+			if actual.NationalInsurance != without.NationalInsurance || actual.Class1ANationalInsurance.Format(2) != "1545.60" {
+				t.Errorf("got {NationalInsurance: %v, Class1ANationalInsurance: %s}, want {NationalInsurance: %v, Class1ANationalInsurance: 1545.60}",
+					actual.NationalInsurance, actual.Class1ANationalInsurance.Format(2), without.NationalInsurance)
+			}
+		})
+	}
+
+	_, err := TaxCalculator{IncomeTaxRates: tax.IncomeTaxRates, NationalInsuranceRates: niRates}.CalculateTakeHome(money.New(50000), tests["AllTaxed"].opts)
+	if err == nil {
+		t.Error("an error was expected but not returned")
+	}
+}
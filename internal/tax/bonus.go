@@ -0,0 +1,303 @@
+package tax
+
+import (
+	"fmt"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+	"github.com/vfc2/tax-calculator/internal/taxcode"
+)
+
+// Number of months in a tax year paid monthly.
+const monthsPerYear = 12
+
+// PayMonth is the pay of a month under PAYE with the tax, National
+// Insurance, student loans and pension contribution deducted from it.
+type PayMonth struct {
+	Pay                       Money
+	Tax                       Money
+	NationalInsurance         Money
+	EmployerNationalInsurance Money
+	StudentLoan               Money
+	PostgraduateLoan          Money
+	PensionContribution       Money
+	TakeHome                  Money
+}
+
+// BonusBreakdown compares a month paid with a one-off bonus to a regular
+// month and to a month where the bonus is sacrificed into a pension.
+type BonusBreakdown struct {
+	Month      int
+	Bonus      Money
+	TaxCode    string
+	Regular    PayMonth
+	BonusMonth PayMonth
+	Sacrificed PayMonth
+	// BonusTax, BonusNationalInsurance, BonusStudentLoan, of both loans,
+	// and BonusPensionContribution are deducted from the bonus in its
+	// month, the rest of the bonus being taken home.
+	BonusTax                 Money
+	BonusNationalInsurance   Money
+	BonusStudentLoan         Money
+	BonusPensionContribution Money
+	BonusTakeHome            Money
+	// MarginalRate is the share of the bonus lost to tax, National
+	// Insurance and student loans in its month.
+	MarginalRate float64
+	// YearlyTax is the tax deducted on the bonus over the year, the tax
+	// overpaid in the bonus month being refunded by the later months,
+	// other than under a non-cumulative code where each month is taxed on
+	// its own.
+	YearlyTax        Money
+	TaxRefundedLater Money
+	// ThresholdEffect is the National Insurance paid on the bonus in its
+	// month less the National Insurance due if it were spread over the
+	// year, negative when the monthly upper earnings limit saves some.
+	ThresholdEffect Money
+	// EmployerNationalInsuranceSaved by sacrificing the bonus.
+	EmployerNationalInsuranceSaved Money
+}
+
+// Calculate the tax and National Insurance of a month of a tax year paid
+// monthly with a one-off bonus on top of a twelfth of the yearly salary.
+// The tax is deducted under PAYE with the tax code, or one giving the
+// personal allowance, and the National Insurance and student loans on the
+// monthly earnings period, at their monthly thresholds. The pension takes
+// a twelfth of its yearly amount and its rate of the pay of each month,
+// deducted as in a yearly calculation. The other options, not deducted
+// from the pay, are left out.
+// Requirements from https://www.gov.uk/government/publications/paye-tax-tables
+// and https://www.gov.uk/guidance/salary-sacrifice-and-the-effects-on-paye
+// and https://www.gov.uk/guidance/special-rules-for-student-loans
+func (t TaxCalculator) CalculateBonus(salary Money, bonus Money, month int, opts Options) (BonusBreakdown, error) {
+	if salary < 0 || bonus <= 0 {
+		return BonusBreakdown{}, fmt.Errorf("the salary cannot be negative and the bonus must be positive")
+	}
+	if month < 1 || month > monthsPerYear {
+		return BonusBreakdown{}, fmt.Errorf("the month %d is not within the months of the year", month)
+	}
+
+	code, err := t.bonusTaxCode(opts)
+	if err != nil {
+		return BonusBreakdown{}, err
+	}
+
+	// The yearly calculations, on the options deducted from the pay, check
+	// them first.
+	payOpts := Options{
+		Residency:        opts.Residency,
+		NICategory:       opts.NICategory,
+		StudentLoan:      opts.StudentLoan,
+		PostgraduateLoan: opts.PostgraduateLoan,
+		Pension:          opts.Pension,
+		TaxCode:          opts.TaxCode,
+	}
+	yearly, err := t.CalculateTakeHome(salary, payOpts)
+	if err != nil {
+		return BonusBreakdown{}, err
+	}
+	yearlyBonus, err := t.CalculateTakeHome(salary+bonus, payOpts)
+	if err != nil {
+		return BonusBreakdown{}, err
+	}
+
+	reliefAtSourceRate := t.IncomeTaxRates[residencyOf(code.Country)].ReliefAtSourceRate
+	pension := opts.Pension
+	pension.Amount = pension.Amount.Div(monthsPerYear)
+
+	// The pay of the month subject to National Insurance and student
+	// loans, the pay subject to tax, and the pension contribution with
+	// the part of it paid from the take home pay.
+	deductPension := func(p Money, sacrificed Money) (Money, Money, Money, Money, error) {
+		contribution, err := pension.calculateContribution(p - sacrificed)
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
+		contribution = contribution.Round(2)
+
+		pay, taxablePay, payment := p-sacrificed, p-sacrificed, contribution
+		switch pension.Scheme {
+		case SalarySacrifice:
+			pay -= contribution
+			taxablePay -= contribution
+		case NetPay:
+			taxablePay -= contribution
+		case ReliefAtSource:
+			payment -= contribution.Mul(reliefAtSourceRate).Round(2)
+		}
+
+		return pay, taxablePay, contribution + sacrificed, payment, nil
+	}
+
+	pay := salary.Div(monthsPerYear).Round(2)
+	payPeriod := PayPeriod{Frequency: Monthly}
+
+	// The year to date before a month, with the bonus paid in its month.
+	yearToDate := func(before int, bonus Money) (YearToDate, error) {
+		var ytd YearToDate
+		for m := 1; m < before; m++ {
+			p := pay
+			if m == month {
+				p += bonus
+			}
+			_, taxablePay, _, _, err := deductPension(p, 0)
+			if err != nil {
+				return YearToDate{}, err
+			}
+			paye, err := t.CalculatePAYE(taxablePay, m, payPeriod, ytd, code)
+			if err != nil {
+				return YearToDate{}, err
+			}
+			ytd = paye.YearToDate
+		}
+
+		return ytd, nil
+	}
+
+	ytd, err := yearToDate(month, 0)
+	if err != nil {
+		return BonusBreakdown{}, err
+	}
+	year, err := yearToDate(monthsPerYear+1, 0)
+	if err != nil {
+		return BonusBreakdown{}, err
+	}
+	yearBonus, err := yearToDate(monthsPerYear+1, bonus)
+	if err != nil {
+		return BonusBreakdown{}, err
+	}
+
+	payMonth := func(p Money, sacrificed Money) (PayMonth, error) {
+		niPay, taxablePay, contribution, payment, err := deductPension(p, sacrificed)
+		if err != nil {
+			return PayMonth{}, err
+		}
+
+		paye, err := t.CalculatePAYE(taxablePay, month, payPeriod, ytd, code)
+		if err != nil {
+			return PayMonth{}, err
+		}
+
+		ni, employerNI, err := t.calculateMonthNationalInsurance(niPay, month, opts.NICategory)
+		if err != nil {
+			return PayMonth{}, err
+		}
+
+		studentLoan, err := t.calculateMonthStudentLoan(niPay, opts.StudentLoan)
+		if err != nil {
+			return PayMonth{}, err
+		}
+		var postgraduateLoan Money
+		if opts.PostgraduateLoan {
+			postgraduateLoan, err = t.calculateMonthStudentLoan(niPay, Postgraduate)
+			if err != nil {
+				return PayMonth{}, err
+			}
+		}
+
+		return PayMonth{
+			Pay:                       p - sacrificed,
+			Tax:                       paye.Tax,
+			NationalInsurance:         ni,
+			EmployerNationalInsurance: employerNI,
+			StudentLoan:               studentLoan,
+			PostgraduateLoan:          postgraduateLoan,
+			PensionContribution:       contribution,
+			TakeHome:                  p - sacrificed - paye.Tax - ni - studentLoan - postgraduateLoan - payment,
+		}, nil
+	}
+
+	regular, err := payMonth(pay, 0)
+	if err != nil {
+		return BonusBreakdown{}, err
+	}
+
+	bonusMonth, err := payMonth(pay+bonus, 0)
+	if err != nil {
+		return BonusBreakdown{}, err
+	}
+
+	sacrificed, err := payMonth(pay+bonus, bonus)
+	if err != nil {
+		return BonusBreakdown{}, err
+	}
+
+	b := BonusBreakdown{
+		Month:                          month,
+		Bonus:                          bonus,
+		TaxCode:                        code.String(),
+		Regular:                        regular,
+		BonusMonth:                     bonusMonth,
+		Sacrificed:                     sacrificed,
+		BonusTax:                       bonusMonth.Tax - regular.Tax,
+		BonusNationalInsurance:         bonusMonth.NationalInsurance - regular.NationalInsurance,
+		BonusStudentLoan:               bonusMonth.StudentLoan + bonusMonth.PostgraduateLoan - regular.StudentLoan - regular.PostgraduateLoan,
+		BonusPensionContribution:       bonusMonth.PensionContribution - regular.PensionContribution,
+		BonusTakeHome:                  bonusMonth.TakeHome - regular.TakeHome,
+		YearlyTax:                      yearBonus.TaxPaid - year.TaxPaid,
+		EmployerNationalInsuranceSaved: bonusMonth.EmployerNationalInsurance - sacrificed.EmployerNationalInsurance,
+	}
+	b.MarginalRate = float64(b.BonusTax+b.BonusNationalInsurance+b.BonusStudentLoan) / float64(bonus)
+	if !code.NonCumulative {
+		b.TaxRefundedLater = b.BonusTax - b.YearlyTax
+	}
+	b.ThresholdEffect = (b.BonusNationalInsurance - (yearlyBonus.NationalInsurance - yearly.NationalInsurance)).Round(2)
+
+	return b, nil
+}
+
+// Find the tax code of a bonus calculation, the code of the options or
+// else the code giving the personal allowance of the residency.
+func (t TaxCalculator) bonusTaxCode(opts Options) (taxcode.Code, error) {
+	if opts.TaxCode != nil {
+		return *opts.TaxCode, nil
+	}
+
+	rates, ok := t.IncomeTaxRates[opts.Residency]
+	if !ok {
+		return taxcode.Code{}, fmt.Errorf("the requested %s residency does not exist", opts.Residency)
+	}
+
+	return taxcode.Code{
+		Country: countryOf(opts.Residency),
+		Kind:    taxcode.Allowance,
+		Number:  int(rates.PersonalAllowance / money.New(10)),
+		Letter:  "L",
+	}, nil
+}
+
+// Calculate the employee and employer National Insurance of the pay of a
+// month, at the rates in force on its last day.
+func (t TaxCalculator) calculateMonthNationalInsurance(pay Money, month int, category string) (Money, Money, error) {
+	periods, err := t.nationalInsurancePeriods(category)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	payday := t.Year.Start().AddDate(0, month, -1)
+	rates := periods[0].rates
+	for _, p := range periods {
+		if !payday.Before(p.From) {
+			rates = p.rates
+		}
+	}
+
+	_, ni := applyBands(t.periodSchedule(rates.Employee, Monthly), pay)
+	_, employerNI := applyBands(t.periodSchedule(rates.Employer, Monthly), pay)
+
+	return ni.Round(2), employerNI.Round(2), nil
+}
+
+// Calculate the student loan repayment of the pay of a month above a
+// twelfth of the yearly threshold, rounded down to the pound.
+func (t TaxCalculator) calculateMonthStudentLoan(pay Money, plan StudentLoanPlan) (Money, error) {
+	if plan == NoStudentLoan {
+		return 0, nil
+	}
+
+	rates, ok := t.StudentLoanRates[plan]
+	if !ok {
+		return 0, fmt.Errorf("the requested %s student loan plan does not exist", plan)
+	}
+
+	return max(pay-rates.Threshold.Div(monthsPerYear), 0).Mul(rates.Rate).RoundDown(0), nil
+}
@@ -0,0 +1,189 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+	"github.com/vfc2/tax-calculator/internal/taxcode"
+)
+
+func TestBonus(t *testing.T) {
+	tax := TaxCalculator{
+		IncomeTaxRates:              map[Residency]IncomeTaxRates{RestOfUK: taxRates},
+		NationalInsuranceRates:      niRates,
+		NationalInsuranceThresholds: niThresholds,
+		StudentLoanRates:            studentLoanRates,
+	}
+	opts := Options{Residency: RestOfUK, NICategory: "A"}
+
+	tests := map[string]struct {
+		salary          Money
+		bonus           Money
+		opts            Options
+		bonusTax        string
+		bonusNI         string
+		yearlyTax       string
+		refunded        string
+		thresholdEffect string
+	}{
+		"BasicRate": {
+			salary:          money.New(30000),
+			bonus:           money.New(5000),
+			opts:            opts,
+			bonusTax:        "1000.00",
+			bonusNI:         "235.12",
+			yearlyTax:       "1000.00",
+			refunded:        "0.00",
+			thresholdEffect: "-264.88",
+		},
+		// The cumulative bands to month 9 push part of the bonus to the
+		// higher rate, the 263.80 overpaid being refunded by the later
+		// months.
+		"HigherRate": {
+			salary:          money.New(45000),
+			bonus:           money.New(10000),
+			opts:            opts,
+			bonusTax:        "3208.00",
+			bonusNI:         "235.12",
+			yearlyTax:       "2944.20",
+			refunded:        "263.80",
+			thresholdEffect: "-387.60",
+		},
+		// Gift Aid is not deducted from the pay, the refund by the later
+		// months staying the same.
+		"GiftAid": {
+			salary:          money.New(45000),
+			bonus:           money.New(10000),
+			opts:            Options{Residency: RestOfUK, NICategory: "A", GiftAid: money.New(4000)},
+			bonusTax:        "3208.00",
+			bonusNI:         "235.12",
+			yearlyTax:       "2944.20",
+			refunded:        "263.80",
+			thresholdEffect: "-387.60",
+		},
+		// Only the monthly primary threshold of the bonus month is left, the
+		// yearly pay staying below the primary threshold.
+		"BelowThreshold": {
+			salary:          money.New(6000),
+			bonus:           money.New(1000),
+			opts:            opts,
+			bonusTax:        "0.00",
+			bonusNI:         "45.20",
+			yearlyTax:       "0.00",
+			refunded:        "0.00",
+			thresholdEffect: "45.20",
+		},
+		"TaxCode": {
+			salary:          money.New(30000),
+			bonus:           money.New(5000),
+			opts:            Options{Residency: RestOfUK, NICategory: "A", TaxCode: &taxcode.Code{Kind: taxcode.BasicRate}},
+			bonusTax:        "1000.00",
+			bonusNI:         "235.12",
+			yearlyTax:       "1000.00",
+			refunded:        "0.00",
+			thresholdEffect: "-264.88",
+		},
+		// The salary sacrifice keeps the yearly pay within the basic rate
+		// band, the bonus month still reaching the higher rate.
+		"SalarySacrifice": {
+			salary:          money.New(45000),
+			bonus:           money.New(10000),
+			opts:            Options{Residency: RestOfUK, NICategory: "A", Pension: Pension{Scheme: SalarySacrifice, Rate: 0.1}},
+			bonusTax:        "2133.00",
+			bonusNI:         "245.12",
+			yearlyTax:       "1800.00",
+			refunded:        "333.00",
+			thresholdEffect: "-654.88",
+		},
+		// Under a month 1 code, the tax overpaid is not refunded by the
+		// later months, the bonus month being taxed on its own.
+		"NonCumulative": {
+			salary:          money.New(45000),
+			bonus:           money.New(10000),
+			opts:            Options{Residency: RestOfUK, NICategory: "A", TaxCode: &taxcode.Code{Kind: taxcode.Allowance, Number: 1257, Letter: "L", NonCumulative: true}},
+			bonusTax:        "4025.40",
+			bonusNI:         "235.12",
+			yearlyTax:       "4025.40",
+			refunded:        "0.00",
+			thresholdEffect: "-387.60",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, err := tax.CalculateBonus(test.salary, test.bonus, 9, test.opts)
+			if err != nil {
+				t.Fatalf("an unexpected error was returned: %v", err)
+			}
+
+			if actual.BonusTax.Format(2) != test.bonusTax || actual.BonusNationalInsurance.Format(2) != test.bonusNI ||
+				actual.YearlyTax.Format(2) != test.yearlyTax || actual.TaxRefundedLater.Format(2) != test.refunded || actual.ThresholdEffect.Format(2) != test.thresholdEffect {
+				t.Errorf("got {BonusTax: %s, BonusNationalInsurance: %s, YearlyTax: %s, TaxRefundedLater: %s, ThresholdEffect: %s}, want {BonusTax: %s, BonusNationalInsurance: %s, YearlyTax: %s, TaxRefundedLater: %s, ThresholdEffect: %s}",
+					actual.BonusTax.Format(2), actual.BonusNationalInsurance.Format(2), actual.YearlyTax.Format(2), actual.TaxRefundedLater.Format(2), actual.ThresholdEffect.Format(2),
+					test.bonusTax, test.bonusNI, test.yearlyTax, test.refunded, test.thresholdEffect)
+			}
+
+			if actual.BonusTakeHome != actual.BonusMonth.TakeHome-actual.Regular.TakeHome {
+				t.Errorf("got BonusTakeHome %v, want %v", actual.BonusTakeHome, actual.BonusMonth.TakeHome-actual.Regular.TakeHome)
+			}
+		})
+	}
+
+	// The bonus sacrificed into the pension leaves a regular month, the
+	// employer saving 13.8% of the bonus.
+	actual, err := tax.CalculateBonus(money.New(45000), money.New(10000), 9, opts)
+	if err != nil {
+		t.Fatalf("an unexpected error was returned: %v", err)
+	}
+	if actual.Sacrificed.TakeHome != actual.Regular.TakeHome || actual.Sacrificed.PensionContribution != money.New(10000) || actual.EmployerNationalInsuranceSaved != money.New(1380) {
+		t.Errorf("got {TakeHome: %v, PensionContribution: %v, EmployerNationalInsuranceSaved: %v}, want {TakeHome: %v, PensionContribution: %v, EmployerNationalInsuranceSaved: %v}",
+			actual.Sacrificed.TakeHome, actual.Sacrificed.PensionContribution, actual.EmployerNationalInsuranceSaved, actual.Regular.TakeHome, money.New(10000), money.New(1380))
+	}
+
+	// The pension and the student loan of each month are on its pay, with
+	// a twelfth of the yearly threshold.
+	actual, err = tax.CalculateBonus(money.New(30000), money.New(5000), 9, Options{Residency: RestOfUK, NICategory: "A", StudentLoan: Plan2, Pension: Pension{Scheme: SalarySacrifice, Rate: 0.05}})
+	if err != nil {
+		t.Fatalf("an unexpected error was returned: %v", err)
+	}
+	if actual.Regular.PensionContribution != money.New(125) || actual.Regular.StudentLoan != money.New(9) || actual.BonusPensionContribution != money.New(250) || actual.BonusStudentLoan != money.New(427) {
+		t.Errorf("got {PensionContribution: %v, StudentLoan: %v, BonusPensionContribution: %v, BonusStudentLoan: %v}, want {PensionContribution: %v, StudentLoan: %v, BonusPensionContribution: %v, BonusStudentLoan: %v}",
+			actual.Regular.PensionContribution, actual.Regular.StudentLoan, actual.BonusPensionContribution, actual.BonusStudentLoan, money.New(125), money.New(9), money.New(250), money.New(427))
+	}
+
+	tests_fail := map[string]struct {
+		bonus Money
+		month int
+		opts  Options
+	}{
+		"NoBonus": {
+			bonus: 0,
+			month: 9,
+			opts:  opts,
+		},
+		"Month13": {
+			bonus: money.New(1000),
+			month: 13,
+			opts:  opts,
+		},
+		"UnknownCategory": {
+			bonus: money.New(1000),
+			month: 9,
+			opts:  Options{Residency: RestOfUK, NICategory: "X"},
+		},
+		"UnknownResidency": {
+			bonus: money.New(1000),
+			month: 9,
+			opts:  Options{Residency: Scotland, NICategory: "A"},
+		},
+	}
+
+	for name, test := range tests_fail {
+		t.Run(name, func(t *testing.T) {
+			_, err := tax.CalculateBonus(money.New(30000), test.bonus, test.month, test.opts)
+			if err == nil {
+				t.Error("an error was expected but not returned")
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package tax
+
+// Share of the child benefit charged for each step of adjusted net income
+// above the threshold, the whole benefit being charged after 100 steps.
+const (
+	chargePerStep = 0.01
+	chargeSteps   = 100
+)
+
+// ChildBenefitRates holds the weekly child benefit of the eldest and of
+// each additional child, and the adjusted net income from which the High
+// Income Child Benefit Charge applies, 1% of the benefit being charged for
+// each step of income above it.
+type ChildBenefitRates struct {
+	EldestChild     Money
+	AdditionalChild Money
+	ChargeThreshold Money
+	ChargeStep      Money
+}
+
+// ChildBenefit describes the child benefit claimed by the taxpayer or
+// their partner, the charge being due from the one with the highest
+// adjusted net income.
+type ChildBenefit struct {
+	Children int
+	Claimed  bool
+}
+
+// Calculate the yearly child benefit of a number of children.
+// Requirements from https://www.gov.uk/child-benefit/what-youll-get
+func (r ChildBenefitRates) calculateChildBenefit(children int) Money {
+	if children <= 0 {
+		return 0
+	}
+
+	weekly := r.EldestChild + r.AdditionalChild*Money(children-1)
+
+	return weekly.Mul(weeksPerYear)
+}
+
+// Calculate the High Income Child Benefit Charge on a yearly child benefit
+// at an adjusted net income, rounded down to the pound.
+// Requirements from https://www.gov.uk/child-benefit-tax-charge
+func (r ChildBenefitRates) calculateCharge(adjustedNetIncome Money, benefit Money) Money {
+	if adjustedNetIncome <= r.ChargeThreshold || r.ChargeStep <= 0 {
+		return 0
+	}
+
+	steps := min(int64((adjustedNetIncome-r.ChargeThreshold)/r.ChargeStep), chargeSteps)
+
+	return benefit.Mul(float64(steps) * chargePerStep).RoundDown(0)
+}
+
+// Income from which the whole child benefit is charged.
+func (r ChildBenefitRates) chargeLimit() Money {
+	return r.ChargeThreshold + r.ChargeStep*chargeSteps
+}
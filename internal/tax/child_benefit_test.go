@@ -0,0 +1,126 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+)
+
+var childBenefitRates = ChildBenefitRates{
+	EldestChild:     money.New(25.60),
+	AdditionalChild: money.New(16.95),
+	ChargeThreshold: money.New(60000),
+	ChargeStep:      money.New(200),
+}
+
+func TestChildBenefit(t *testing.T) {
+	tests := map[string]struct {
+		children int
+		expected Money
+	}{
+		"NoChildren": {
+			children: 0,
+			expected: 0,
+		},
+		"OneChild": {
+			children: 1,
+			expected: money.New(1331.20),
+		},
+		"ThreeChildren": {
+			children: 3,
+			expected: money.New(3094),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := childBenefitRates.calculateChildBenefit(test.children)
+
+			if actual != test.expected {
+				t.Errorf("got %v, want %v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestChildBenefitCharge(t *testing.T) {
+	tests := map[string]struct {
+		rates             ChildBenefitRates
+		adjustedNetIncome Money
+		expected          Money
+	}{
+		"BelowThreshold": {
+			rates:             childBenefitRates,
+			adjustedNetIncome: money.New(60000),
+			expected:          0,
+		},
+		"WithinStep": {
+			rates:             childBenefitRates,
+			adjustedNetIncome: money.New(60199),
+			expected:          0,
+		},
+		"HalfCharged": {
+			rates:             childBenefitRates,
+			adjustedNetIncome: money.New(70100),
+			expected:          money.New(665),
+		},
+		"FullyCharged": {
+			rates:             childBenefitRates,
+			adjustedNetIncome: money.New(90000),
+			expected:          money.New(1331),
+		},
+		"EarlierTaper": {
+			rates:             ChildBenefitRates{ChargeThreshold: money.New(50000), ChargeStep: money.New(100)},
+			adjustedNetIncome: money.New(55000),
+			expected:          money.New(665),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := test.rates.calculateCharge(test.adjustedNetIncome, money.New(1331.20))
+
+			if actual != test.expected {
+				t.Errorf("got %v, want %v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestTakeHomeChildBenefitCharge(t *testing.T) {
+	tax := TaxCalculator{
+		IncomeTaxRates:         map[Residency]IncomeTaxRates{RestOfUK: taxRates},
+		NationalInsuranceRates: niRates,
+		ChildBenefitRates:      childBenefitRates,
+	}
+	opts := Options{Residency: RestOfUK, NICategory: "A", ChildBenefit: ChildBenefit{Children: 2, Claimed: true}}
+
+	actual, err := tax.CalculateTakeHome(money.New(75000), opts)
+	if err != nil {
+		t.Fatalf("an unexpected error was returned: %v", err)
+	}
+
+	// 75% of a yearly benefit of 2212.60, the charge being deducted from
+	// the take home.
+	without, _ := tax.CalculateTakeHome(money.New(75000), Options{Residency: RestOfUK, NICategory: "A"})
+	if actual.ChildBenefit.Format(2) != "2212.60" || actual.ChildBenefitCharge != money.New(1659) || actual.TakeHome != without.TakeHome-money.New(1659) {
+		t.Errorf("got {ChildBenefit: %s, ChildBenefitCharge: %v, TakeHome: %v}, want {ChildBenefit: 2212.60, ChildBenefitCharge: %v, TakeHome: %v}",
+			actual.ChildBenefit.Format(2), actual.ChildBenefitCharge, actual.TakeHome, money.New(1659), without.TakeHome-money.New(1659))
+	}
+
+	// A pension contribution reduces the adjusted net income.
+	opts.Pension = Pension{Scheme: NetPay, Amount: money.New(15000)}
+	actual, err = tax.CalculateTakeHome(money.New(75000), opts)
+	if err != nil {
+		t.Fatalf("an unexpected error was returned: %v", err)
+	}
+	if actual.AdjustedNetIncome != money.New(60000) || actual.ChildBenefitCharge != 0 {
+		t.Errorf("got {AdjustedNetIncome: %v, ChildBenefitCharge: %v}, want {AdjustedNetIncome: %v, ChildBenefitCharge: 0}",
+			actual.AdjustedNetIncome, actual.ChildBenefitCharge, money.New(60000))
+	}
+
+	_, err = TaxCalculator{IncomeTaxRates: tax.IncomeTaxRates, NationalInsuranceRates: niRates}.CalculateTakeHome(money.New(75000), opts)
+	if err == nil {
+		t.Error("an error was expected but not returned")
+	}
+}
@@ -0,0 +1,145 @@
+package tax
+
+import (
+	"fmt"
+)
+
+// Rate of the apprenticeship levy on the pay bill of an umbrella company,
+// its allowance being used up by its other employees.
+const apprenticeshipLevyRate = 0.005
+
+// Engagement is how a contractor is engaged by a client.
+type Engagement string
+
+const (
+	Permanent   Engagement = "Permanent employee"
+	Umbrella    Engagement = "Umbrella company"
+	InsideIR35  Engagement = "Limited company inside IR35"
+	OutsideIR35 Engagement = "Limited company outside IR35"
+)
+
+// Contract describes the work of a contractor for a year. The umbrella
+// margin is charged each week of five days worked, the company costs,
+// e.g. accountancy, each year.
+type Contract struct {
+	DayRate        Money
+	Days           float64
+	UmbrellaMargin Money
+	CompanyCosts   Money
+}
+
+// ContractScenario is the take home of a contract for an Engagement, with
+// the costs deducted from the revenue of the contract before the salary
+// and dividends are paid.
+type ContractScenario struct {
+	Engagement                Engagement
+	Revenue                   Money
+	Margin                    Money
+	EmployerNationalInsurance Money
+	ApprenticeshipLevy        Money
+	CompanyCosts              Money
+	CorporationTax            Money
+	Salary                    Money
+	Dividends                 Money
+	Breakdown                 IncomeTaxBreakdown
+	TakeHome                  Money
+}
+
+// Compare the take home of a contract as a permanent employee paid the
+// revenue of the contract, through an umbrella company, and through a
+// limited company inside and outside IR35.
+// Inside IR35 the fee-payer deducts employer National Insurance from the
+// revenue and pays the rest as a deemed employment, the company costs
+// coming out of the take home. Outside IR35 the profit is extracted with
+// the optimal split of salary and dividends.
+// Requirements from https://www.gov.uk/guidance/understanding-off-payroll-working-ir35
+func (t TaxCalculator) CompareContract(c Contract, opts Options) ([]ContractScenario, error) {
+	if c.DayRate <= 0 || c.Days <= 0 {
+		return nil, fmt.Errorf("the contract of %v days at %s is invalid", c.Days, c.DayRate.Format(2))
+	}
+
+	revenue := c.DayRate.Mul(c.Days)
+
+	permanent := ContractScenario{Engagement: Permanent, Revenue: revenue, Salary: revenue}
+
+	umbrella := ContractScenario{Engagement: Umbrella, Revenue: revenue, Margin: c.UmbrellaMargin.Mul(c.Days / defaultDaysPerWeek)}
+	salary, err := t.calculateSalaryForCost(revenue-umbrella.Margin, apprenticeshipLevyRate, opts)
+	if err != nil {
+		return nil, err
+	}
+	umbrella.Salary = salary
+	umbrella.ApprenticeshipLevy = salary.Mul(apprenticeshipLevyRate)
+
+	inside := ContractScenario{Engagement: InsideIR35, Revenue: revenue, CompanyCosts: c.CompanyCosts}
+	inside.Salary, err = t.calculateSalaryForCost(revenue, 0, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	scenarios := []ContractScenario{permanent, umbrella, inside}
+	for i, s := range scenarios {
+		s.Breakdown, err = t.CalculateTakeHome(s.Salary, opts)
+		if err != nil {
+			return nil, err
+		}
+		// The employer of a permanent employee pays its National
+		// Insurance on top of the salary.
+		if s.Engagement != Permanent {
+			s.EmployerNationalInsurance = s.Breakdown.EmployerNationalInsurance
+		}
+		s.TakeHome = s.Breakdown.TakeHome - s.CompanyCosts
+		scenarios[i] = s
+	}
+
+	outside := ContractScenario{Engagement: OutsideIR35, Revenue: revenue, CompanyCosts: c.CompanyCosts}
+	if revenue > c.CompanyCosts {
+		extraction, err := t.OptimiseExtraction(revenue-c.CompanyCosts, opts)
+		if err != nil {
+			return nil, err
+		}
+		outside.EmployerNationalInsurance = extraction.Optimal.EmployerNationalInsurance
+		outside.CorporationTax = extraction.Optimal.CorporationTax
+		outside.Salary = extraction.Optimal.Salary
+		outside.Dividends = extraction.Optimal.Dividends
+		outside.Breakdown = extraction.Optimal.Breakdown
+		outside.TakeHome = extraction.Optimal.Breakdown.TakeHome
+	}
+
+	return append(scenarios, outside), nil
+}
+
+// Calculate the highest yearly salary whose cost, including employer
+// National Insurance and the apprenticeship levy at a rate, fits within
+// a budget. The cost never decreases when the salary increases, so the
+// salary is found by bisection to the penny.
+func (t TaxCalculator) calculateSalaryForCost(budget Money, levyRate float64, opts Options) (Money, error) {
+	cost := func(salary Money) (Money, error) {
+		ni, err := t.calculateYearNationalInsurance(salary, opts.NICategory, false, 0)
+		return salary + ni.EmployerNationalInsurance + salary.Mul(levyRate), err
+	}
+
+	if budget <= 0 {
+		return 0, nil
+	}
+
+	// The highest salary fitting the budget is within [lo, hi).
+	lo, hi := Money(0), budget+penny
+	for hi-lo > penny {
+		mid := (lo + (hi-lo)/2).RoundDown(2)
+		if mid <= lo {
+			mid = lo + penny
+		}
+
+		c, err := cost(mid)
+		if err != nil {
+			return 0, err
+		}
+		if c <= budget {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	return lo, nil
+}
@@ -0,0 +1,67 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+)
+
+func TestCompareContract(t *testing.T) {
+	tax := TaxCalculator{
+		IncomeTaxRates:         map[Residency]IncomeTaxRates{RestOfUK: taxRates},
+		NationalInsuranceRates: niRates,
+		DividendRates:          dividendRates,
+		CorporationTaxRates:    corporationTaxRates,
+	}
+	opts := Options{Residency: RestOfUK, NICategory: "A"}
+	contract := Contract{
+		DayRate:        money.New(500),
+		Days:           200,
+		UmbrellaMargin: money.New(20),
+		CompanyCosts:   money.New(1500),
+	}
+
+	actual, err := tax.CompareContract(contract, opts)
+	if err != nil {
+		t.Fatalf("an unexpected error was returned: %v", err)
+	}
+
+	engagements := []Engagement{Permanent, Umbrella, InsideIR35, OutsideIR35}
+	if len(actual) != len(engagements) {
+		t.Fatalf("got %d scenarios, want %d", len(actual), len(engagements))
+	}
+	for i, s := range actual {
+		if s.Engagement != engagements[i] || s.Revenue != money.New(100000) {
+			t.Errorf("got the scenario %s with a revenue of %v, want %s with a revenue of %v", s.Engagement, s.Revenue, engagements[i], money.New(100000))
+		}
+	}
+
+	permanent, umbrella, inside, outside := actual[0], actual[1], actual[2], actual[3]
+
+	if permanent.Salary != money.New(100000) || permanent.EmployerNationalInsurance != 0 {
+		t.Errorf("got a permanent salary of %v with %v of employer NI, want %v with none", permanent.Salary, permanent.EmployerNationalInsurance, money.New(100000))
+	}
+
+	// A margin of 20 a week over 40 weeks, the salary, employer NI and the
+	// levy using up the rest of the revenue to the penny.
+	cost := umbrella.Salary + umbrella.EmployerNationalInsurance + umbrella.ApprenticeshipLevy + umbrella.Margin
+	if umbrella.Margin != money.New(800) || cost > umbrella.Revenue || cost+penny.Mul(1.2) < umbrella.Revenue {
+		t.Errorf("got an umbrella margin of %v and a cost of %v, want %v and %v", umbrella.Margin, cost, money.New(800), umbrella.Revenue)
+	}
+
+	// Employer NI above an ST of 175 a week, with the salary and 13.8% of
+	// the weekly salary above the ST using up the revenue.
+	if inside.Salary.Format(2) != "88976.97" || inside.TakeHome != inside.Breakdown.TakeHome-contract.CompanyCosts {
+		t.Errorf("got an inside IR35 salary of %s and a take home of %v, want 88976.97 and %v", inside.Salary.Format(2), inside.TakeHome, inside.Breakdown.TakeHome-contract.CompanyCosts)
+	}
+
+	if outside.Salary != money.New(12570) || outside.TakeHome <= inside.TakeHome || outside.TakeHome <= umbrella.TakeHome {
+		t.Errorf("got an outside IR35 salary of %v and take homes of %v, %v and %v, want %v and the highest take home",
+			outside.Salary, outside.TakeHome, inside.TakeHome, umbrella.TakeHome, money.New(12570))
+	}
+
+	_, err = tax.CompareContract(Contract{DayRate: money.New(500)}, opts)
+	if err == nil {
+		t.Error("an error was expected but not returned")
+	}
+}
@@ -0,0 +1,207 @@
+package tax
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+)
+
+// Increase of salary between two splits searched by the optimiser.
+var extractionStep = money.New(100)
+
+// Smallest difference of salary between the optimal split and the
+// runners-up, so they show different strategies.
+var runnerUpGap = money.New(1000)
+
+// Number of runners-up and of rows of the trade-off table of an Extraction.
+const (
+	runnersUp     = 3
+	tradeOffSteps = 20
+)
+
+// Highest number of splits searched by the optimiser.
+const maxExtractionSplits = 100000
+
+// CorporationTaxRates holds the small profits and main rates of the
+// financial year starting in the tax year, profits between the lower and
+// upper limits getting marginal relief. A single rate applies when the
+// limits are 0.
+type CorporationTaxRates struct {
+	SmallProfitsRate       float64
+	MainRate               float64
+	LowerLimit             Money
+	UpperLimit             Money
+	MarginalReliefFraction float64
+}
+
+// ExtractionSplit is the extraction of the profit of a company as a
+// director's salary, the remaining profit after corporation tax being paid
+// as dividends.
+type ExtractionSplit struct {
+	Salary                    Money
+	EmployerNationalInsurance Money
+	CorporationTax            Money
+	Dividends                 Money
+	Breakdown                 IncomeTaxBreakdown
+}
+
+// Extraction is the result of the search of the split of a profit giving
+// the highest take home.
+type Extraction struct {
+	Profit    Money
+	Optimal   ExtractionSplit
+	RunnersUp []ExtractionSplit
+	TradeOff  []ExtractionSplit
+}
+
+// Calculate the corporation tax of a yearly taxable profit.
+// Requirements from https://www.gov.uk/guidance/corporation-tax-marginal-relief
+func (r CorporationTaxRates) calculateCorporationTax(profit Money) Money {
+	switch {
+	case profit <= 0:
+		return 0
+	case profit <= r.LowerLimit:
+		return profit.Mul(r.SmallProfitsRate)
+	case profit < r.UpperLimit:
+		return profit.Mul(r.MainRate) - (r.UpperLimit - profit).Mul(r.MarginalReliefFraction)
+	default:
+		return profit.Mul(r.MainRate)
+	}
+}
+
+// Calculate the extraction of a yearly profit paying a salary to the
+// director. The salary and its employer National Insurance are deducted
+// from the profit before corporation tax, the director's National
+// Insurance uses the annual earnings period.
+func (t TaxCalculator) CalculateExtraction(profit Money, salary Money, opts Options) (ExtractionSplit, error) {
+	opts.Director = true
+
+	ni, err := t.calculateYearNationalInsurance(salary, opts.NICategory, true, 0)
+	if err != nil {
+		return ExtractionSplit{}, err
+	}
+
+	taxable := profit - salary - ni.EmployerNationalInsurance
+	if taxable < 0 {
+		return ExtractionSplit{}, fmt.Errorf("the salary of %s and its National Insurance exceed the profit of %s", salary.Format(2), profit.Format(2))
+	}
+
+	split := ExtractionSplit{
+		Salary:                    salary,
+		EmployerNationalInsurance: ni.EmployerNationalInsurance,
+		CorporationTax:            t.CorporationTaxRates.calculateCorporationTax(taxable),
+	}
+	split.Dividends = taxable - split.CorporationTax
+
+	opts.Dividends += split.Dividends
+	split.Breakdown, err = t.CalculateTakeHome(salary, opts)
+	if err != nil {
+		return ExtractionSplit{}, err
+	}
+
+	return split, nil
+}
+
+// Search the split of a yearly profit between a director's salary and
+// dividends giving the highest take home. Salaries are searched in steps
+// and at the thresholds of the National Insurance category and the
+// personal allowance, where the take home changes trend.
+func (t TaxCalculator) OptimiseExtraction(profit Money, opts Options) (Extraction, error) {
+	if profit <= 0 {
+		return Extraction{}, fmt.Errorf("the profit of %s is invalid", profit.Format(2))
+	}
+	if int64(profit/extractionStep) >= maxExtractionSplits {
+		return Extraction{}, fmt.Errorf("the profit of %s is too high to be searched", profit.Format(2))
+	}
+
+	var tradeOff []Money
+	for i := range tradeOffSteps + 1 {
+		tradeOff = append(tradeOff, (profit / tradeOffSteps * Money(i)).Round(0))
+	}
+
+	var salaries []Money
+	for s := Money(0); s <= profit; s += extractionStep {
+		salaries = append(salaries, s)
+	}
+	salaries = append(salaries, tradeOff...)
+	salaries = append(salaries, t.extractionThresholds(opts)...)
+	slices.Sort(salaries)
+	salaries = slices.Compact(salaries)
+
+	var splits []ExtractionSplit
+	for _, s := range salaries {
+		split, err := t.CalculateExtraction(profit, s, opts)
+		if err != nil && len(splits) == 0 {
+			return Extraction{}, err
+		}
+		if err != nil {
+			// The salary and its National Insurance exceed the profit.
+			break
+		}
+		splits = append(splits, split)
+	}
+
+	e := Extraction{Profit: profit}
+
+	for i, s := range splits {
+		if slices.Contains(tradeOff, s.Salary) || i == len(splits)-1 {
+			e.TradeOff = append(e.TradeOff, s)
+		}
+	}
+
+	best := slices.Clone(splits)
+	slices.SortStableFunc(best, func(a, b ExtractionSplit) int {
+		switch {
+		case a.Breakdown.TakeHome > b.Breakdown.TakeHome:
+			return -1
+		case a.Breakdown.TakeHome < b.Breakdown.TakeHome:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	e.Optimal = best[0]
+	for _, s := range best[1:] {
+		if len(e.RunnersUp) == runnersUp {
+			break
+		}
+		distinct := absMoney(s.Salary-e.Optimal.Salary) >= runnerUpGap
+		for _, r := range e.RunnersUp {
+			distinct = distinct && absMoney(s.Salary-r.Salary) >= runnerUpGap
+		}
+		if distinct {
+			e.RunnersUp = append(e.RunnersUp, s)
+		}
+	}
+
+	return e, nil
+}
+
+// Yearly salaries at the thresholds of the National Insurance category and
+// at the personal allowance.
+func (t TaxCalculator) extractionThresholds(opts Options) []Money {
+	var thresholds []Money
+
+	var bands []Band
+	if rates, ok := t.DirectorNationalInsuranceRates[opts.NICategory]; ok {
+		bands = slices.Concat(rates.Employee, rates.Employer)
+	} else if rates, ok := t.NationalInsuranceRates[opts.NICategory]; ok {
+		bands = slices.Concat(t.periodSchedule(rates.Employee, Annually), t.periodSchedule(rates.Employer, Annually))
+	}
+	for _, b := range bands {
+		if b.Max != 0 {
+			thresholds = append(thresholds, b.Max)
+		}
+	}
+	if rates, ok := t.IncomeTaxRates[opts.Residency]; ok {
+		thresholds = append(thresholds, rates.PersonalAllowance)
+	}
+
+	return thresholds
+}
+
+func absMoney(m Money) Money {
+	return max(m, -m)
+}
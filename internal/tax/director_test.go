@@ -0,0 +1,128 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+)
+
+var corporationTaxRates = CorporationTaxRates{
+	SmallProfitsRate:       0.19,
+	MainRate:               0.25,
+	LowerLimit:             money.New(50000),
+	UpperLimit:             money.New(250000),
+	MarginalReliefFraction: 0.015,
+}
+
+func TestCorporationTax(t *testing.T) {
+	tests := map[string]struct {
+		rates    CorporationTaxRates
+		profit   Money
+		expected Money
+	}{
+		"Loss": {
+			rates:    corporationTaxRates,
+			profit:   money.New(-1000),
+			expected: 0,
+		},
+		"SmallProfits": {
+			rates:    corporationTaxRates,
+			profit:   money.New(50000),
+			expected: money.New(9500),
+		},
+		"MarginalRelief": {
+			rates:    corporationTaxRates,
+			profit:   money.New(100000),
+			expected: money.New(22750),
+		},
+		"Main": {
+			rates:    corporationTaxRates,
+			profit:   money.New(300000),
+			expected: money.New(75000),
+		},
+		"SingleRate": {
+			rates:    CorporationTaxRates{SmallProfitsRate: 0.19, MainRate: 0.19},
+			profit:   money.New(100000),
+			expected: money.New(19000),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := test.rates.calculateCorporationTax(test.profit)
+
+			if actual != test.expected {
+				t.Errorf("got %v, want %v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestExtraction(t *testing.T) {
+	tax := TaxCalculator{
+		IncomeTaxRates:         map[Residency]IncomeTaxRates{RestOfUK: taxRates},
+		NationalInsuranceRates: niRates,
+		DividendRates:          dividendRates,
+		CorporationTaxRates:    corporationTaxRates,
+	}
+	opts := Options{Residency: RestOfUK, NICategory: "A"}
+
+	actual, err := tax.CalculateExtraction(money.New(60000), money.New(12570), opts)
+	if err != nil {
+		t.Fatalf("an unexpected error was returned: %v", err)
+	}
+
+	// Employer NI on 12,570 above an ST of 9,100, corporation tax at the
+	// small profits rate on the rest of the profit.
+	if actual.EmployerNationalInsurance.Format(2) != "478.86" || actual.CorporationTax.Format(2) != "8920.72" || actual.Dividends.Format(2) != "38030.42" {
+		t.Errorf("got {EmployerNationalInsurance: %s, CorporationTax: %s, Dividends: %s}, want {EmployerNationalInsurance: 478.86, CorporationTax: 8920.72, Dividends: 38030.42}",
+			actual.EmployerNationalInsurance.Format(2), actual.CorporationTax.Format(2), actual.Dividends.Format(2))
+	}
+
+	_, err = tax.CalculateExtraction(money.New(10000), money.New(12570), opts)
+	if err == nil {
+		t.Error("an error was expected but not returned")
+	}
+}
+
+func TestOptimiseExtraction(t *testing.T) {
+	tax := TaxCalculator{
+		IncomeTaxRates:         map[Residency]IncomeTaxRates{RestOfUK: taxRates},
+		NationalInsuranceRates: niRates,
+		DividendRates:          dividendRates,
+		CorporationTaxRates:    corporationTaxRates,
+	}
+	opts := Options{Residency: RestOfUK, NICategory: "A"}
+
+	actual, err := tax.OptimiseExtraction(money.New(60000), opts)
+	if err != nil {
+		t.Fatalf("an unexpected error was returned: %v", err)
+	}
+
+	for _, s := range append(actual.RunnersUp, actual.TradeOff...) {
+		if s.Breakdown.TakeHome > actual.Optimal.Breakdown.TakeHome {
+			t.Errorf("got a split of salary %v with a higher take home than the optimal one", s.Salary)
+		}
+	}
+
+	if len(actual.RunnersUp) != runnersUp {
+		t.Errorf("got %d runners-up, want %d", len(actual.RunnersUp), runnersUp)
+	}
+
+	// The salary is best at the personal allowance, just below the PT.
+	if actual.Optimal.Salary != money.New(12570) {
+		t.Errorf("got an optimal salary of %v, want %v", actual.Optimal.Salary, money.New(12570))
+	}
+
+	// The trade-off goes from no salary to the highest salary the profit
+	// can pay.
+	last := actual.TradeOff[len(actual.TradeOff)-1]
+	if actual.TradeOff[0].Salary != 0 || last.Salary+last.EmployerNationalInsurance > actual.Profit || last.Salary+last.EmployerNationalInsurance+extractionStep.Mul(1.138) <= actual.Profit {
+		t.Errorf("got a trade-off of salaries from %v to %v, want from 0 to the profit", actual.TradeOff[0].Salary, last.Salary)
+	}
+
+	_, err = tax.OptimiseExtraction(0, opts)
+	if err == nil {
+		t.Error("an error was expected but not returned")
+	}
+}
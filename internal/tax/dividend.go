@@ -0,0 +1,50 @@
+package tax
+
+// Name of the dividend band matching the basic rate band.
+const ordinaryRateBand = "Ordinary"
+
+// DividendRates holds the dividend allowance and the ordered schedule of
+// dividend rates. The bands are on taxable income and are the UK ones
+// whatever the residency, Scottish rates applying to non-savings income
+// only.
+type DividendRates struct {
+	Allowance Money
+	Bands     []Band
+}
+
+// Calculate the tax on taxable dividends stacked on top of the taxable
+// income already in the bands. The allowance is taxed at 0% but uses up
+// the bands it falls in.
+// Requirements from https://www.gov.uk/tax-on-dividends
+func (r DividendRates) calculateDividendTax(taxable Money, dividends Money) ([]BandBreakdown, Money) {
+	allowance := min(r.Allowance, dividends)
+
+	bands, tax := applyBandsFrom(r.Bands, taxable+allowance, dividends-allowance)
+	bands = append([]BandBreakdown{{Name: "Dividend Allowance", Amount: allowance}}, bands...)
+
+	return bands, tax
+}
+
+// Extend the ordinary rate band, and every limit above it, by a gross
+// contribution.
+func (r DividendRates) extendBands(by Money) DividendRates {
+	r.Bands = extendBandsAfter(r.Bands, ordinaryRateBand, by)
+
+	return r
+}
+
+// Apply an ordered schedule of bands to an amount stacked on top of
+// another one already in the bands.
+func applyBandsFrom(bands []Band, from Money, amount Money) ([]BandBreakdown, Money) {
+	breakdown, _ := applyBands(bands, from+amount)
+	below, _ := applyBands(bands, from)
+	var total Money
+
+	for i := range breakdown {
+		breakdown[i].Amount -= below[i].Amount
+		breakdown[i].Tax = breakdown[i].Amount.Mul(breakdown[i].Rate)
+		total += breakdown[i].Tax
+	}
+
+	return breakdown, total
+}
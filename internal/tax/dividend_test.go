@@ -0,0 +1,154 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+)
+
+var dividendRates = DividendRates{
+	Allowance: money.New(500),
+	Bands: []Band{
+		{Name: "Ordinary", Min: 0, Max: money.New(37700), Rate: 0.0875},
+		{Name: "Upper", Min: money.New(37700), Max: money.New(125140), Rate: 0.3375},
+		{Name: "Additional", Min: money.New(125140), Max: 0, Rate: 0.3935},
+	},
+}
+
+func TestDividendTax(t *testing.T) {
+	tests := map[string]struct {
+		taxable   Money
+		dividends Money
+		expected  Money
+	}{
+		"AllowanceOnly": {
+			taxable:   money.New(10000),
+			dividends: money.New(300),
+			expected:  0,
+		},
+		"Ordinary": {
+			taxable:   money.New(10000),
+			dividends: money.New(5000),
+			expected:  money.New(393.75),
+		},
+		"Straddling": {
+			taxable:   money.New(35000),
+			dividends: money.New(10000),
+			expected:  money.New(2656.25),
+		},
+		"Additional": {
+			taxable:   money.New(130000),
+			dividends: money.New(10000),
+			expected:  money.New(3738.25),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			bands, actual := dividendRates.calculateDividendTax(test.taxable, test.dividends)
+
+			if actual != test.expected {
+				t.Errorf("got %v, want %v", actual, test.expected)
+			}
+
+			var total Money
+			for _, b := range bands {
+				total += b.Amount
+			}
+			if total != test.dividends {
+				t.Errorf("got %v of dividends in the bands, want %v", total, test.dividends)
+			}
+		})
+	}
+}
+
+func TestTakeHomeDividends(t *testing.T) {
+	tests := map[string]struct {
+		income            Money
+		opts              Options
+		expectedAllowance Money
+		expectedDividend  Money
+		expectedTakeHome  string
+	}{
+		"DividendsOnly": {
+			income:            0,
+			opts:              Options{Residency: RestOfUK, NICategory: "A", Dividends: money.New(19870)},
+			expectedAllowance: money.New(12570),
+			expectedDividend:  money.New(595),
+			expectedTakeHome:  "19275.00",
+		},
+		"AllowanceTaper": {
+			income:            money.New(100000),
+			opts:              Options{Residency: RestOfUK, NICategory: "A", Dividends: money.New(10000)},
+			expectedAllowance: money.New(7570),
+			expectedDividend:  money.New(3206.25),
+			expectedTakeHome:  "72597.43",
+		},
+		"ScottishTaxpayer": {
+			income:            money.New(50000),
+			opts:              Options{Residency: Scotland, NICategory: "A", Dividends: money.New(5500)},
+			expectedAllowance: money.New(12570),
+			expectedDividend:  money.New(1687.50),
+			expectedTakeHome:  "41042.59",
+		},
+		"ReliefAtSource": {
+			income: money.New(50000),
+			opts: Options{
+				Residency:  RestOfUK,
+				NICategory: "A",
+				Pension:    Pension{Scheme: ReliefAtSource, Amount: money.New(5000)},
+				Dividends:  money.New(5000),
+			},
+			expectedAllowance: money.New(12570),
+			expectedDividend:  money.New(393.75),
+			expectedTakeHome:  "39378.65",
+		},
+	}
+
+	noDividendRates := investmentCalculator
+	noDividendRates.DividendRates = DividendRates{}
+
+	tests_fail := map[string]struct {
+		tax  TaxCalculator
+		opts Options
+	}{
+		"NoDividendRates": {
+			tax:  noDividendRates,
+			opts: Options{Residency: RestOfUK, NICategory: "A", Dividends: money.New(1000)},
+		},
+		"ResidencyDoesntExist": {
+			tax:  investmentCalculator,
+			opts: Options{Residency: "Atlantis", NICategory: "A", Dividends: money.New(1000)},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, err := investmentCalculator.CalculateTakeHome(test.income, test.opts)
+			if err != nil {
+				t.Fatalf("an unexpected error was returned: %v", err)
+			}
+
+			if actual.Allowance != test.expectedAllowance {
+				t.Errorf("got allowance %v, want %v", actual.Allowance, test.expectedAllowance)
+			}
+
+			if actual.DividendTax != test.expectedDividend {
+				t.Errorf("got dividend tax %v, want %v", actual.DividendTax, test.expectedDividend)
+			}
+
+			if takeHome := actual.TakeHome.Format(2); takeHome != test.expectedTakeHome {
+				t.Errorf("got take home %s, want %s", takeHome, test.expectedTakeHome)
+			}
+		})
+	}
+
+	for name, test := range tests_fail {
+		t.Run(name, func(t *testing.T) {
+			_, err := test.tax.CalculateTakeHome(money.New(30000), test.opts)
+			if err == nil {
+				t.Error("an error was expected but not returned")
+			}
+		})
+	}
+}
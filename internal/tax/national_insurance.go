@@ -0,0 +1,236 @@
+package tax
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Number of weekly pay periods National Insurance is calculated over in a
+// yearly calculation.
+const weeksPerYear = 52
+
+// NationalInsuranceChange replaces the National Insurance rates of some
+// categories from a date within the tax year, e.g. the main rate going
+// from 12% to 10% on 6 January 2024.
+type NationalInsuranceChange struct {
+	From  time.Time
+	Rates map[string]NationalInsuranceRates
+}
+
+// NationalInsuranceThreshold is a threshold of the weekly National
+// Insurance bands with its amounts over a month and a year, as published
+// by HMRC rather than pro rata of the weekly amount, e.g. a PT of £242 a
+// week, £1,048 a month and £12,570 a year.
+// Requirements from https://www.gov.uk/guidance/rates-and-thresholds-for-employers-2025-to-2026
+type NationalInsuranceThreshold struct {
+	Name  string
+	Week  Money
+	Month Money
+	Year  Money
+}
+
+// NationalInsurancePeriod is the National Insurance due over the weeks of
+// a tax year paid at the same rates.
+type NationalInsurancePeriod struct {
+	From                      time.Time
+	To                        time.Time
+	Weeks                     int
+	NationalInsurance         Money
+	EmployerNationalInsurance Money
+	rates                     NationalInsuranceRates
+}
+
+// UnmarshalJSON decodes a NationalInsuranceChange, its date being written
+// as 2024-01-06.
+func (c *NationalInsuranceChange) UnmarshalJSON(data []byte) error {
+	var config struct {
+		From  string
+		Rates map[string]NationalInsuranceRates
+	}
+
+	err := json.Unmarshal(data, &config)
+	if err != nil {
+		return err
+	}
+
+	from, err := time.Parse(time.DateOnly, config.From)
+	if err != nil {
+		return fmt.Errorf("the date of the National Insurance change is invalid: %w", err)
+	}
+
+	c.From = from
+	c.Rates = config.Rates
+
+	return nil
+}
+
+// Split the weeks of the tax year by the rates of a Category applying on
+// their last day, National Insurance being due at the rates in force when
+// the pay is received.
+func (t TaxCalculator) nationalInsurancePeriods(category string) ([]NationalInsurancePeriod, error) {
+	rates, ok := t.NationalInsuranceRates[category]
+	if !ok {
+		return nil, fmt.Errorf("the requested %s Category does not exist", category)
+	}
+
+	changes := slices.Clone(t.NationalInsuranceChanges)
+	slices.SortFunc(changes, func(a, b NationalInsuranceChange) int { return a.From.Compare(b.From) })
+
+	periods := []NationalInsurancePeriod{{From: t.Year.Start(), rates: rates}}
+
+	for week := 1; week <= weeksPerYear; week++ {
+		last := &periods[len(periods)-1]
+		payday := t.Year.Start().AddDate(0, 0, 7*week-1)
+
+		changed := false
+		for len(changes) > 0 && !payday.Before(changes[0].From) {
+			if r, ok := changes[0].Rates[category]; ok {
+				rates = r
+				changed = true
+			}
+			changes = changes[1:]
+		}
+
+		if changed && last.Weeks > 0 {
+			periods = append(periods, NationalInsurancePeriod{From: last.To.AddDate(0, 0, 1), rates: rates})
+			last = &periods[len(periods)-1]
+		}
+
+		last.rates = rates
+		last.Weeks++
+		last.To = payday
+	}
+	periods[len(periods)-1].To = t.Year.End()
+
+	return periods, nil
+}
+
+// Calculate the yearly employee and employer National Insurance of a
+// Category on weekly pay, each week at the rates of its period, the weeks
+// of an extra payday being paid at the rates of the last one. Directors
+// use an annual earnings period instead, the yearly thresholds applying
+// to the pay of the year, pro rata of the weeks of each period, or the
+// annual thresholds and rates published for directors when the rates
+// change within the year.
+// Requirements from https://www.gov.uk/guidance/national-insurance-contributions-for-company-directors
+func (t TaxCalculator) calculateYearNationalInsurance(pay Money, category string, director bool, extraWeeks int) (IncomeTaxBreakdown, error) {
+	periods, err := t.nationalInsurancePeriods(category)
+	if err != nil {
+		return IncomeTaxBreakdown{}, err
+	}
+
+	if rates, ok := t.DirectorNationalInsuranceRates[category]; ok && director {
+		bands, ni := applyBands(rates.Employee, pay)
+		_, employerNI := applyBands(rates.Employer, pay)
+
+		return IncomeTaxBreakdown{
+			NationalInsurance:         ni,
+			EmployerNationalInsurance: employerNI,
+			NationalInsuranceBands:    bands,
+		}, nil
+	}
+
+	weeks := weeksPerYear
+	if !director {
+		weeks += extraWeeks
+		periods[len(periods)-1].Weeks += extraWeeks
+	}
+
+	tax := IncomeTaxBreakdown{}
+
+	for i, p := range periods {
+		earnings, periodsPaid := pay.Div(float64(weeks)), float64(p.Weeks)
+		employee, employer := p.rates.Employee, p.rates.Employer
+		if director {
+			earnings, periodsPaid = pay, float64(p.Weeks)/weeksPerYear
+			employee, employer = t.periodSchedule(employee, Annually), t.periodSchedule(employer, Annually)
+		}
+
+		bands, ni := applyBands(employee, earnings)
+		_, employerNI := applyBands(employer, earnings)
+
+		periods[i].NationalInsurance = ni.Mul(periodsPaid)
+		periods[i].EmployerNationalInsurance = employerNI.Mul(periodsPaid)
+
+		tax.NationalInsurance += periods[i].NationalInsurance
+		tax.EmployerNationalInsurance += periods[i].EmployerNationalInsurance
+		tax.NationalInsuranceBands = mergeBands(tax.NationalInsuranceBands, scaleBands(bands, periodsPaid))
+	}
+
+	if len(periods) > 1 {
+		tax.NationalInsurancePeriods = periods
+	}
+
+	return tax, nil
+}
+
+// Thresholds of a schedule of weekly bands over an earnings period of a
+// payroll frequency, taken from the NationalInsuranceThresholds of the
+// year and otherwise scaled from the weekly thresholds.
+func (t TaxCalculator) periodSchedule(bands []Band, frequency Frequency) []Band {
+	weeks := float64(weeksPerYear) / float64(PayPeriod{Frequency: frequency}.Periods())
+	scaled := scaleSchedule(bands, weeks)
+
+	for _, threshold := range t.NationalInsuranceThresholds {
+		amount, ok := threshold.amount(frequency)
+		if !ok {
+			continue
+		}
+
+		for i, b := range bands {
+			if b.Min == threshold.Week && b.Min != 0 {
+				scaled[i].Min = amount
+			}
+			if b.Max == threshold.Week && b.Max != 0 {
+				scaled[i].Max = amount
+			}
+		}
+	}
+
+	return scaled
+}
+
+// Amount of a threshold over an earnings period of a payroll frequency.
+func (th NationalInsuranceThreshold) amount(frequency Frequency) (Money, bool) {
+	switch frequency {
+	case Annually:
+		return th.Year, th.Year != 0
+	case Monthly:
+		return th.Month, th.Month != 0
+	default:
+		return 0, false
+	}
+}
+
+// Scale the thresholds of a schedule of bands by a number of periods.
+func scaleSchedule(bands []Band, periods float64) []Band {
+	scaled := make([]Band, len(bands))
+
+	for i, b := range bands {
+		b.Min = b.Min.Mul(periods)
+		b.Max = b.Max.Mul(periods)
+		scaled[i] = b
+	}
+
+	return scaled
+}
+
+// Add the amounts of a breakdown of bands to another, by band name.
+func mergeBands(into []BandBreakdown, bands []BandBreakdown) []BandBreakdown {
+	for _, b := range bands {
+		i := slices.IndexFunc(into, func(m BandBreakdown) bool { return m.Name == b.Name })
+		if i < 0 {
+			into = append(into, b)
+			continue
+		}
+		into[i].Amount += b.Amount
+		into[i].Tax += b.Tax
+		if into[i].Amount > 0 {
+			into[i].Rate = float64(into[i].Tax) / float64(into[i].Amount)
+		}
+	}
+
+	return into
+}
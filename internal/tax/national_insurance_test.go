@@ -0,0 +1,210 @@
+package tax
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+)
+
+var niThresholds = []NationalInsuranceThreshold{
+	{Name: "LEL", Week: money.New(123), Month: money.New(533), Year: money.New(6396)},
+	{Name: "PT", Week: money.New(242), Month: money.New(1048), Year: money.New(12570)},
+	{Name: "ST", Week: money.New(175), Month: money.New(758), Year: money.New(9100)},
+	{Name: "UEL, UST, AUST and VUST", Week: money.New(967), Month: money.New(4189), Year: money.New(50270)},
+}
+
+func TestYearNationalInsurance(t *testing.T) {
+	cut := niRates["A"]
+	cut.Employee = []Band{
+		{Name: "Up to LEL", Min: 0, Max: money.New(123), Rate: 0},
+		{Name: "LEL to PT", Min: money.New(123), Max: money.New(242), Rate: 0},
+		{Name: "PT to UEL", Min: money.New(242), Max: money.New(967), Rate: 0.06},
+		{Name: "Above UEL", Min: money.New(967), Max: 0, Rate: 0.02},
+	}
+
+	tests := map[string]struct {
+		changes          []NationalInsuranceChange
+		category         string
+		extraWeeks       int
+		expected         string
+		expectedEmployer string
+		expectedWeeks    []int
+	}{
+		"NoChange": {
+			category:         "A",
+			expected:         "1741.60",
+			expectedEmployer: "2884.20",
+		},
+		"MidYearCut": {
+			changes: []NationalInsuranceChange{
+				{From: time.Date(2024, time.January, 6, 0, 0, 0, 0, time.UTC), Rates: map[string]NationalInsuranceRates{"A": cut}},
+			},
+			category:         "A",
+			expected:         "1567.44",
+			expectedEmployer: "2884.20",
+			expectedWeeks:    []int{39, 13},
+		},
+		// The week 53 payday has its own earnings period, at the rates of
+		// the last weeks.
+		"Week53": {
+			category:         "A",
+			extraWeeks:       1,
+			expected:         "1717.40",
+			expectedEmployer: "2860.05",
+		},
+		"MidYearCutWeek53": {
+			changes: []NationalInsuranceChange{
+				{From: time.Date(2024, time.January, 6, 0, 0, 0, 0, time.UTC), Rates: map[string]NationalInsuranceRates{"A": cut}},
+			},
+			category:         "A",
+			extraWeeks:       1,
+			expected:         "1535.94",
+			expectedEmployer: "2860.05",
+			expectedWeeks:    []int{39, 14},
+		},
+		"OtherCategoryChanged": {
+			changes: []NationalInsuranceChange{
+				{From: time.Date(2024, time.January, 6, 0, 0, 0, 0, time.UTC), Rates: map[string]NationalInsuranceRates{"A": cut}},
+			},
+			category:         "M",
+			expected:         "1393.28",
+			expectedEmployer: "0.00",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tax := TaxCalculator{
+				Year:                     2023,
+				NationalInsuranceRates:   niRates,
+				NationalInsuranceChanges: test.changes,
+			}
+
+			actual, err := tax.calculateYearNationalInsurance(money.New(30000), test.category, false, test.extraWeeks)
+			if err != nil {
+				t.Fatalf("an unexpected error was returned: %v", err)
+			}
+
+			ni := actual.NationalInsurance.Format(2)
+			employerNI := actual.EmployerNationalInsurance.Format(2)
+
+			if ni != test.expected || employerNI != test.expectedEmployer {
+				t.Errorf("got {NationalInsurance: %s, EmployerNationalInsurance: %s}, want {NationalInsurance: %s, EmployerNationalInsurance: %s}",
+					ni, employerNI, test.expected, test.expectedEmployer)
+			}
+
+			var weeks []int
+			for _, p := range actual.NationalInsurancePeriods {
+				weeks = append(weeks, p.Weeks)
+			}
+			if len(weeks) != len(test.expectedWeeks) {
+				t.Fatalf("got periods of %v weeks, want %v", weeks, test.expectedWeeks)
+			}
+			for i := range weeks {
+				if weeks[i] != test.expectedWeeks[i] {
+					t.Errorf("got periods of %v weeks, want %v", weeks, test.expectedWeeks)
+				}
+			}
+		})
+	}
+
+	tax := TaxCalculator{NationalInsuranceRates: niRates}
+	_, err := tax.calculateYearNationalInsurance(money.New(30000), "ZZ", false, 0)
+	if err == nil {
+		t.Error("an error was expected but not returned")
+	}
+}
+
+func TestDirectorNationalInsurance(t *testing.T) {
+	tax := TaxCalculator{
+		Year:                        2024,
+		NationalInsuranceRates:      niRates,
+		NationalInsuranceThresholds: niThresholds,
+	}
+
+	// The annual PT and UEL are 12,570 and 50,270, not 52 times the
+	// weekly thresholds.
+	actual, err := tax.calculateYearNationalInsurance(money.New(60000), "A", true, 0)
+	if err != nil {
+		t.Fatalf("an unexpected error was returned: %v", err)
+	}
+
+	ni := actual.NationalInsurance.Format(2)
+	employerNI := actual.EmployerNationalInsurance.Format(2)
+
+	if ni != "3964.60" || employerNI != "7024.20" {
+		t.Errorf("got {NationalInsurance: %s, EmployerNationalInsurance: %s}, want {NationalInsurance: 3964.60, EmployerNationalInsurance: 7024.20}",
+			ni, employerNI)
+	}
+
+	thresholds := tax.extractionThresholds(Options{NICategory: "A"})
+	for _, expected := range []Money{money.New(6396), money.New(12570), money.New(50270), money.New(9100)} {
+		if !slices.Contains(thresholds, expected) {
+			t.Errorf("got thresholds %v, want %v among them", thresholds, expected)
+		}
+	}
+
+	// Without published thresholds, the weekly ones are scaled.
+	tax.NationalInsuranceThresholds = nil
+	actual, _ = tax.calculateYearNationalInsurance(money.New(60000), "A", true, 0)
+	if actual.NationalInsurance.Format(2) != "3964.32" {
+		t.Errorf("got %s, want 3964.32", actual.NationalInsurance.Format(2))
+	}
+
+	// The rates changing within 2022/23, directors use the annual PT of
+	// 11,908 and the blended rates of 12.73%, 2.73% and 14.53%.
+	tax.Year = 2022
+	tax.DirectorNationalInsuranceRates = map[string]NationalInsuranceRates{
+		"A": {
+			Employee: []Band{
+				{Name: "Up to LEL", Min: 0, Max: money.New(6396), Rate: 0},
+				{Name: "LEL to PT", Min: money.New(6396), Max: money.New(11908), Rate: 0},
+				{Name: "PT to UEL", Min: money.New(11908), Max: money.New(50270), Rate: 0.1273},
+				{Name: "Above UEL", Min: money.New(50270), Max: 0, Rate: 0.0273},
+			},
+			Employer: []Band{
+				{Name: "Up to ST", Min: 0, Max: money.New(9100), Rate: 0},
+				{Name: "Above ST", Min: money.New(9100), Max: 0, Rate: 0.1453},
+			},
+		},
+	}
+
+	actual, err = tax.calculateYearNationalInsurance(money.New(50270), "A", true, 0)
+	if err != nil {
+		t.Fatalf("an unexpected error was returned: %v", err)
+	}
+
+	ni = actual.NationalInsurance.Format(2)
+	employerNI = actual.EmployerNationalInsurance.Format(2)
+
+	if ni != "4883.48" || employerNI != "5982.00" {
+		t.Errorf("got {NationalInsurance: %s, EmployerNationalInsurance: %s}, want {NationalInsurance: 4883.48, EmployerNationalInsurance: 5982.00}",
+			ni, employerNI)
+	}
+
+	thresholds = tax.extractionThresholds(Options{NICategory: "A"})
+	if !slices.Contains(thresholds, money.New(11908)) {
+		t.Errorf("got thresholds %v, want %v among them", thresholds, money.New(11908))
+	}
+}
+
+func TestNationalInsuranceChangeConfig(t *testing.T) {
+	var changes []NationalInsuranceChange
+
+	err := json.Unmarshal([]byte(`[{"From": "2024-01-06", "Rates": {"A": {"Employee": [], "Employer": []}}}]`), &changes)
+	if err != nil {
+		t.Fatalf("an unexpected error was returned: %v", err)
+	}
+
+	if len(changes) != 1 || !changes[0].From.Equal(time.Date(2024, time.January, 6, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("got %v, want a change from 2024-01-06", changes)
+	}
+
+	err = json.Unmarshal([]byte(`[{"From": "6 January 2024", "Rates": {}}]`), &changes)
+	if err == nil {
+		t.Error("an error was expected but not returned")
+	}
+}
@@ -0,0 +1,112 @@
+package tax
+
+import (
+	"fmt"
+
+	"github.com/vfc2/tax-calculator/internal/taxcode"
+)
+
+// Share of the pay of a period that K codes can take as tax.
+const regulatoryLimit = 0.5
+
+// YearToDate holds the totals of the previous periods of the tax year,
+// as reported on a P45 or payslip.
+type YearToDate struct {
+	TaxablePay Money
+	TaxPaid    Money
+}
+
+// PAYEBreakdown is the tax due for a single pay period.
+type PAYEBreakdown struct {
+	Period  int
+	Pay     Money
+	FreePay Money
+	// TaxablePay is the pay to date, less the free pay, rounded down to
+	// the pound.
+	TaxablePay Money
+	TaxDue     Money
+	// Tax is deducted for the period, it is negative for a refund.
+	Tax        Money
+	Regulated  bool
+	YearToDate YearToDate
+}
+
+// Calculate the tax of a pay period under PAYE. The period is numbered
+// from 1 within the tax year of the payroll frequency. Cumulative codes
+// spread the allowance and bands over the periods to date, non-cumulative
+// (W1/M1) codes and extra paydays (week 53, 54 or 56) treat the period as
+// the first one. K codes cannot take more than half of the pay.
+// Requirements from https://www.gov.uk/government/publications/paye-tax-tables
+func (t TaxCalculator) CalculatePAYE(pay Money, period int, payPeriod PayPeriod, ytd YearToDate, code taxcode.Code) (PAYEBreakdown, error) {
+	periods := payPeriod.Periods()
+	if periods == 0 {
+		return PAYEBreakdown{}, fmt.Errorf("the requested %s pay period is not a payroll frequency", payPeriod.Frequency)
+	}
+	if period < 1 || float64(period) > payPeriod.PerYear() {
+		return PAYEBreakdown{}, fmt.Errorf("the period %d is not within the periods of the year", period)
+	}
+
+	residency := residencyOf(code.Country)
+	rates, ok := t.IncomeTaxRates[residency]
+	if !ok {
+		return PAYEBreakdown{}, fmt.Errorf("the requested %s residency does not exist", residency)
+	}
+
+	rates, allowance, err := rates.applyTaxCode(code)
+	if err != nil {
+		return PAYEBreakdown{}, err
+	}
+
+	n := period
+	previous := ytd
+	if code.NonCumulative || period > periods {
+		n = 1
+		previous = YearToDate{}
+	}
+	share := float64(n) / float64(periods)
+
+	freePay := allowance.Mul(share).RoundUp(2)
+	taxablePay := max(previous.TaxablePay+pay-freePay, 0).RoundDown(0)
+
+	bands, _ := applyBands(rates.prorateBands(share).Bands, taxablePay)
+	var taxDue Money
+	for _, b := range bands {
+		taxDue += b.Tax.RoundDown(2)
+	}
+
+	tax := taxDue - previous.TaxPaid
+	regulated := false
+	if code.Kind == taxcode.Deduction && tax > pay.Mul(regulatoryLimit) {
+		tax = pay.Mul(regulatoryLimit).RoundDown(2)
+		regulated = true
+	}
+
+	return PAYEBreakdown{
+		Period:     period,
+		Pay:        pay,
+		FreePay:    freePay,
+		TaxablePay: taxablePay,
+		TaxDue:     taxDue,
+		Tax:        tax,
+		Regulated:  regulated,
+		YearToDate: YearToDate{
+			TaxablePay: ytd.TaxablePay + pay,
+			TaxPaid:    ytd.TaxPaid + tax,
+		},
+	}, nil
+}
+
+// Prorate the band limits to a share of the year, rounded up to the pound.
+func (r IncomeTaxRates) prorateBands(share float64) IncomeTaxRates {
+	bands := make([]Band, len(r.Bands))
+
+	for i, b := range r.Bands {
+		b.Min = b.Min.Mul(share).RoundUp(0)
+		b.Max = b.Max.Mul(share).RoundUp(0)
+		bands[i] = b
+	}
+
+	r.Bands = bands
+
+	return r
+}
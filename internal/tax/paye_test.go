@@ -0,0 +1,154 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+	"github.com/vfc2/tax-calculator/internal/taxcode"
+)
+
+func TestPAYE(t *testing.T) {
+	tests := map[string]struct {
+		code              string
+		pay               Money
+		period            int
+		payPeriod         PayPeriod
+		ytd               YearToDate
+		expectedTax       string
+		expectedTaxable   string
+		expectedRegulated bool
+	}{
+		"FirstMonth": {
+			code:            "1257L",
+			pay:             money.New(3000),
+			period:          1,
+			payPeriod:       PayPeriod{Frequency: Monthly},
+			expectedTax:     "390.20",
+			expectedTaxable: "1951.00",
+		},
+		"SecondMonth": {
+			code:            "1257L",
+			pay:             money.New(3000),
+			period:          2,
+			payPeriod:       PayPeriod{Frequency: Monthly},
+			ytd:             YearToDate{TaxablePay: money.New(3000), TaxPaid: money.New(390.20)},
+			expectedTax:     "390.40",
+			expectedTaxable: "3903.00",
+		},
+		"VaryingPay": {
+			code:            "1257L",
+			pay:             money.New(9000),
+			period:          3,
+			payPeriod:       PayPeriod{Frequency: Monthly},
+			ytd:             YearToDate{TaxablePay: money.New(6000), TaxPaid: money.New(780.60)},
+			expectedTax:     "2076.40",
+			expectedTaxable: "11855.00",
+		},
+		"Refund": {
+			code:            "1257L",
+			pay:             0,
+			period:          2,
+			payPeriod:       PayPeriod{Frequency: Monthly},
+			ytd:             YearToDate{TaxablePay: money.New(3000), TaxPaid: money.New(390.20)},
+			expectedTax:     "-209.60",
+			expectedTaxable: "903.00",
+		},
+		"NonCumulative": {
+			code:            "1257L M1",
+			pay:             money.New(3000),
+			period:          6,
+			payPeriod:       PayPeriod{Frequency: Monthly},
+			ytd:             YearToDate{TaxablePay: money.New(15000), TaxPaid: money.New(1000)},
+			expectedTax:     "390.20",
+			expectedTaxable: "1951.00",
+		},
+		"Week53": {
+			code:            "1257L",
+			pay:             money.New(700),
+			period:          53,
+			payPeriod:       PayPeriod{Frequency: Weekly, ExtraPayday: true},
+			ytd:             YearToDate{TaxablePay: money.New(36400), TaxPaid: money.New(4763.20)},
+			expectedTax:     "91.60",
+			expectedTaxable: "458.00",
+		},
+		"K": {
+			code:            "K475",
+			pay:             money.New(1000),
+			period:          1,
+			payPeriod:       PayPeriod{Frequency: Monthly},
+			expectedTax:     "279.20",
+			expectedTaxable: "1396.00",
+		},
+		"KRegulatoryLimit": {
+			code:              "K5000",
+			pay:               money.New(500),
+			period:            1,
+			payPeriod:         PayPeriod{Frequency: Monthly},
+			expectedTax:       "250.00",
+			expectedTaxable:   "4667.00",
+			expectedRegulated: true,
+		},
+	}
+
+	tax := TaxCalculator{
+		IncomeTaxRates: map[Residency]IncomeTaxRates{RestOfUK: taxRates},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			code, err := taxcode.Parse(test.code)
+			if err != nil {
+				t.Fatalf("an unexpected error was returned: %v", err)
+			}
+
+			actual, err := tax.CalculatePAYE(test.pay, test.period, test.payPeriod, test.ytd, code)
+			if err != nil {
+				t.Fatalf("an unexpected error was returned: %v", err)
+			}
+
+			taxed := actual.Tax.Format(2)
+			taxable := actual.TaxablePay.Format(2)
+
+			if taxed != test.expectedTax || taxable != test.expectedTaxable || actual.Regulated != test.expectedRegulated {
+				t.Errorf("got {Tax: %s, TaxablePay: %s, Regulated: %v}, want {Tax: %s, TaxablePay: %s, Regulated: %v}",
+					taxed, taxable, actual.Regulated, test.expectedTax, test.expectedTaxable, test.expectedRegulated)
+			}
+
+			if actual.YearToDate.TaxablePay != test.ytd.TaxablePay+test.pay || actual.YearToDate.TaxPaid != test.ytd.TaxPaid+actual.Tax {
+				t.Errorf("got year to date %v, want it to include the period", actual.YearToDate)
+			}
+		})
+	}
+
+	tests_fail := map[string]struct {
+		period    int
+		payPeriod PayPeriod
+	}{
+		"PeriodZero": {
+			period:    0,
+			payPeriod: PayPeriod{Frequency: Monthly},
+		},
+		"PeriodAfterYearEnd": {
+			period:    13,
+			payPeriod: PayPeriod{Frequency: Monthly},
+		},
+		"Week53WithoutExtraPayday": {
+			period:    53,
+			payPeriod: PayPeriod{Frequency: Weekly},
+		},
+		"NotAPayrollFrequency": {
+			period:    1,
+			payPeriod: PayPeriod{Frequency: Hourly},
+		},
+	}
+
+	for name, test := range tests_fail {
+		t.Run(name, func(t *testing.T) {
+			_, err := tax.CalculatePAYE(money.New(3000), test.period, test.payPeriod, YearToDate{}, taxcode.Code{Kind: taxcode.Allowance, Number: 1257, Letter: "L"})
+
+			if err == nil {
+				t.Error("an error was expected but not returned")
+			}
+		})
+	}
+}
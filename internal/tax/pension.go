@@ -0,0 +1,79 @@
+package tax
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PensionScheme determines how pension contributions receive tax relief.
+type PensionScheme string
+
+const (
+	NoPension       PensionScheme = ""
+	NetPay          PensionScheme = "Net pay"
+	ReliefAtSource  PensionScheme = "Relief at source"
+	SalarySacrifice PensionScheme = "Salary sacrifice"
+)
+
+// Name of the band extended by relief at source contributions.
+const basicRateBand = "Basic"
+
+// ErrContributionAboveIncome is returned when the pension contribution is
+// larger than the income it is paid from.
+var ErrContributionAboveIncome = errors.New("the pension contribution exceeds the income")
+
+// Pension describes the employee contribution, either as a Rate of the
+// gross income or as a fixed yearly Amount, or both.
+type Pension struct {
+	Scheme PensionScheme
+	Rate   float64
+	Amount Money
+}
+
+// Calculate the gross yearly contribution paid into the pension, which
+// cannot be larger than the income it is paid from.
+func (p Pension) calculateContribution(income Money) (Money, error) {
+	switch p.Scheme {
+	case NoPension:
+		return 0, nil
+	case NetPay, ReliefAtSource, SalarySacrifice:
+		contribution := income.Mul(p.Rate) + p.Amount
+		if contribution > income {
+			return 0, fmt.Errorf("%w: %s of %s", ErrContributionAboveIncome, contribution.Format(2), income.Format(2))
+		}
+		return contribution, nil
+	default:
+		return 0, fmt.Errorf("the requested %s pension scheme does not exist", p.Scheme)
+	}
+}
+
+// Extend the basic rate band, and every limit above it, by a gross
+// contribution.
+// Requirements from https://www.gov.uk/tax-on-your-private-pension/pension-tax-relief
+func (r IncomeTaxRates) extendBands(by Money) IncomeTaxRates {
+	r.Bands = extendBandsAfter(r.Bands, basicRateBand, by)
+
+	return r
+}
+
+// Extend a band of a schedule, and every limit above it, by an amount.
+func extendBandsAfter(bands []Band, name string, by Money) []Band {
+	extended := make([]Band, len(bands))
+	copy(extended, bands)
+
+	extend := false
+	for i, b := range extended {
+		if extend {
+			b.Min += by
+		}
+		if b.Name == name {
+			extend = true
+		}
+		if extend && b.Max != 0 {
+			b.Max += by
+		}
+		extended[i] = b
+	}
+
+	return extended
+}
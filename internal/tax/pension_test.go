@@ -0,0 +1,252 @@
+package tax
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+)
+
+func TestPensionContribution(t *testing.T) {
+	tests := map[string]struct {
+		pension  Pension
+		income   Money
+		expected Money
+	}{
+		"NoPension": {
+			pension:  Pension{Scheme: NoPension, Rate: 0.05},
+			income:   money.New(40000),
+			expected: 0,
+		},
+		"Rate": {
+			pension:  Pension{Scheme: NetPay, Rate: 0.05},
+			income:   money.New(40000),
+			expected: money.New(2000),
+		},
+		"Amount": {
+			pension:  Pension{Scheme: ReliefAtSource, Amount: money.New(1200)},
+			income:   money.New(40000),
+			expected: money.New(1200),
+		},
+	}
+
+	tests_fail := map[string]struct {
+		pension Pension
+		income  Money
+	}{
+		"SchemeDoesntExist": {
+			pension: Pension{Scheme: "Defined benefit", Rate: 0.05},
+			income:  money.New(40000),
+		},
+		"AmountAboveIncome": {
+			pension: Pension{Scheme: SalarySacrifice, Amount: money.New(20000)},
+			income:  money.New(10000),
+		},
+		"RateAboveIncome": {
+			pension: Pension{Scheme: NetPay, Rate: 1.5},
+			income:  money.New(10000),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, _ := test.pension.calculateContribution(test.income)
+
+			if actual != test.expected {
+				t.Errorf("got %v, want %v", actual, test.expected)
+			}
+		})
+	}
+
+	for name, test := range tests_fail {
+		t.Run(name, func(t *testing.T) {
+			actual, err := test.pension.calculateContribution(test.income)
+
+			if actual != 0 || err == nil {
+				t.Error("an error was expected but not returned")
+			}
+		})
+	}
+}
+
+func TestExtendBands(t *testing.T) {
+	tests := map[string]struct {
+		rates    IncomeTaxRates
+		expected []Band
+	}{
+		"RestOfUK": {
+			rates: taxRates,
+			expected: []Band{
+				{Name: "Basic", Min: 0, Max: money.New(38700), Rate: 0.2},
+				{Name: "Higher", Min: money.New(38700), Max: money.New(126140), Rate: 0.4},
+				{Name: "Additional", Min: money.New(126140), Max: 0, Rate: 0.45},
+			},
+		},
+		"Scotland": {
+			rates: scottishTaxRates,
+			expected: []Band{
+				{Name: "Starter", Min: 0, Max: money.New(2306), Rate: 0.19},
+				{Name: "Basic", Min: money.New(2306), Max: money.New(14991), Rate: 0.2},
+				{Name: "Intermediate", Min: money.New(14991), Max: money.New(32092), Rate: 0.21},
+				{Name: "Higher", Min: money.New(32092), Max: money.New(63430), Rate: 0.42},
+				{Name: "Advanced", Min: money.New(63430), Max: money.New(126140), Rate: 0.45},
+				{Name: "Top", Min: money.New(126140), Max: 0, Rate: 0.48},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := test.rates.extendBands(money.New(1000))
+
+			if !reflect.DeepEqual(actual.Bands, test.expected) {
+				t.Errorf("got %v, want %v", actual.Bands, test.expected)
+			}
+		})
+	}
+}
+
+func TestPensionTakeHome(t *testing.T) {
+	tests := map[string]struct {
+		income           Money
+		pension          Pension
+		expectedTakeHome string
+		expectedTaxed    string
+		expectedNI       string
+		expectedRelief   string
+	}{
+		"NetPay": {
+			income:           money.New(63450),
+			pension:          Pension{Scheme: NetPay, Rate: 0.05},
+			expectedTakeHome: "44701.18",
+			expectedTaxed:    "11543.00",
+			expectedNI:       "4033.32",
+			expectedRelief:   "0.00",
+		},
+		"ReliefAtSource": {
+			income:           money.New(63450),
+			pension:          Pension{Scheme: ReliefAtSource, Rate: 0.05},
+			expectedTakeHome: "44701.18",
+			expectedTaxed:    "12177.50",
+			expectedNI:       "4033.32",
+			expectedRelief:   "634.50",
+		},
+		"SalarySacrifice": {
+			income:           money.New(63450),
+			pension:          Pension{Scheme: SalarySacrifice, Rate: 0.05},
+			expectedTakeHome: "44764.63",
+			expectedTaxed:    "11543.00",
+			expectedNI:       "3969.87",
+			expectedRelief:   "0.00",
+		},
+		"TaperOnAdjustedNetIncome": {
+			income:           money.New(110000),
+			pension:          Pension{Scheme: NetPay, Amount: money.New(10000)},
+			expectedTakeHome: "67603.68",
+			expectedTaxed:    "27432.00",
+			expectedNI:       "4964.32",
+			expectedRelief:   "0.00",
+		},
+	}
+
+	tax := TaxCalculator{
+		IncomeTaxRates:         map[Residency]IncomeTaxRates{RestOfUK: taxRates},
+		NationalInsuranceRates: niRates,
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := Options{Residency: RestOfUK, NICategory: "A", Pension: test.pension}
+			actual, _ := tax.CalculateTakeHome(test.income, opts)
+
+			takeHome := actual.TakeHome.Format(2)
+			taxed := actual.Taxed.Format(2)
+			ni := actual.NationalInsurance.Format(2)
+			relief := actual.PensionTaxRelief.Format(2)
+
+			if takeHome != test.expectedTakeHome || taxed != test.expectedTaxed || ni != test.expectedNI || relief != test.expectedRelief {
+				t.Errorf("got {TakeHome: %s, Taxed: %s, National Insurance: %s, Relief: %s}, want {TakeHome: %s, Taxed: %s, National Insurance: %s, Relief: %s}",
+					takeHome, taxed, ni, relief, test.expectedTakeHome, test.expectedTaxed, test.expectedNI, test.expectedRelief)
+			}
+		})
+	}
+}
+
+func TestGiftAidTakeHome(t *testing.T) {
+	tests := map[string]struct {
+		income            Money
+		giftAid           Money
+		expectedGross     Money
+		expectedTaxed     Money
+		expectedRelief    Money
+		expectedAllowance Money
+	}{
+		"BasicRate": {
+			income:            money.New(30000),
+			giftAid:           money.New(800),
+			expectedGross:     money.New(1000),
+			expectedTaxed:     money.New(3486),
+			expectedRelief:    0,
+			expectedAllowance: money.New(12570),
+		},
+		"HigherRate": {
+			income:            money.New(63450),
+			giftAid:           money.New(800),
+			expectedGross:     money.New(1000),
+			expectedTaxed:     money.New(12612),
+			expectedRelief:    money.New(200),
+			expectedAllowance: money.New(12570),
+		},
+		// The donation brings the adjusted net income down to the taper
+		// threshold, restoring the whole personal allowance.
+		"TaperOnAdjustedNetIncome": {
+			income:            money.New(110000),
+			giftAid:           money.New(8000),
+			expectedGross:     money.New(10000),
+			expectedTaxed:     money.New(29432),
+			expectedRelief:    money.New(4000),
+			expectedAllowance: money.New(12570),
+		},
+	}
+
+	tax := TaxCalculator{
+		IncomeTaxRates:         map[Residency]IncomeTaxRates{RestOfUK: taxRates},
+		NationalInsuranceRates: niRates,
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := Options{Residency: RestOfUK, NICategory: "A", GiftAid: test.giftAid}
+			actual, err := tax.CalculateTakeHome(test.income, opts)
+			if err != nil {
+				t.Fatalf("an unexpected error was returned: %v", err)
+			}
+
+			if actual.GiftAid != test.expectedGross || actual.Taxed != test.expectedTaxed || actual.HigherRateRelief != test.expectedRelief || actual.Allowance != test.expectedAllowance {
+				t.Errorf("got {GiftAid: %v, Taxed: %v, HigherRateRelief: %v, Allowance: %v}, want {GiftAid: %v, Taxed: %v, HigherRateRelief: %v, Allowance: %v}",
+					actual.GiftAid, actual.Taxed, actual.HigherRateRelief, actual.Allowance, test.expectedGross, test.expectedTaxed, test.expectedRelief, test.expectedAllowance)
+			}
+
+			without, _ := tax.CalculateTakeHome(test.income, Options{Residency: RestOfUK, NICategory: "A"})
+			if actual.TakeHome != without.TakeHome-test.giftAid+test.expectedRelief {
+				t.Errorf("got a take home of %v, want %v", actual.TakeHome, without.TakeHome-test.giftAid+test.expectedRelief)
+			}
+		})
+	}
+
+	// Relief at source contributions and Gift Aid extend the bands
+	// together.
+	opts := Options{Residency: RestOfUK, NICategory: "A", Pension: Pension{Scheme: ReliefAtSource, Rate: 0.05}, GiftAid: money.New(800)}
+	actual, err := tax.CalculateTakeHome(money.New(63450), opts)
+	if err != nil {
+		t.Fatalf("an unexpected error was returned: %v", err)
+	}
+	if actual.HigherRateRelief.Format(2) != "834.50" {
+		t.Errorf("got a higher rate relief of %s, want 834.50", actual.HigherRateRelief.Format(2))
+	}
+
+	_, err = tax.CalculateTakeHome(money.New(63450), Options{Residency: RestOfUK, NICategory: "A", GiftAid: money.New(-100)})
+	if err == nil {
+		t.Error("an error was expected but not returned")
+	}
+}
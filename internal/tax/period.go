@@ -0,0 +1,142 @@
+package tax
+
+import (
+	"fmt"
+)
+
+// Frequency is how often pay is received.
+type Frequency string
+
+const (
+	Annually    Frequency = "Year"
+	Monthly     Frequency = "Month"
+	FourWeekly  Frequency = "4 Weeks"
+	Fortnightly Frequency = "Fortnight"
+	Weekly      Frequency = "Week"
+	Daily       Frequency = "Day"
+	Hourly      Frequency = "Hour"
+)
+
+// Defaults of a PayPeriod when a working pattern is not provided.
+const (
+	defaultHoursPerWeek = 37.5
+	defaultDaysPerWeek  = 5
+	defaultWeeksPerYear = 52
+)
+
+// PayPeriod converts pay between a Frequency and a yearly amount. Daily
+// and hourly pay use the working pattern, weekly based payrolls can
+// have an extra payday in the tax year, i.e. a week 53 for a weekly
+// payroll, 54 for a fortnightly one or 56 for a four-weekly one.
+type PayPeriod struct {
+	Frequency    Frequency
+	HoursPerWeek float64
+	DaysPerWeek  float64
+	WeeksPerYear float64
+	ExtraPayday  bool
+}
+
+// Periods returns the standard number of payroll periods in a tax year,
+// or 0 when the Frequency is not a payroll frequency.
+func (p PayPeriod) Periods() int {
+	switch p.Frequency {
+	case Annually:
+		return 1
+	case Monthly:
+		return 12
+	case FourWeekly:
+		return 13
+	case Fortnightly:
+		return 26
+	case Weekly:
+		return 52
+	default:
+		return 0
+	}
+}
+
+// PerYear returns the number of periods paid in a year.
+func (p PayPeriod) PerYear() float64 {
+	weeks := p.WeeksPerYear
+	if weeks == 0 {
+		weeks = defaultWeeksPerYear
+	}
+
+	switch p.Frequency {
+	case Daily:
+		days := p.DaysPerWeek
+		if days == 0 {
+			days = defaultDaysPerWeek
+		}
+		return days * weeks
+	case Hourly:
+		hours := p.HoursPerWeek
+		if hours == 0 {
+			hours = defaultHoursPerWeek
+		}
+		return hours * weeks
+	case FourWeekly, Fortnightly, Weekly:
+		if p.ExtraPayday {
+			return float64(p.Periods() + 1)
+		}
+	}
+
+	return float64(p.Periods())
+}
+
+// ExtraPaydayWeeks returns the number of weeks of the earnings period of
+// the extra payday, e.g. 1 for a week 53, or 0 without one.
+func (p PayPeriod) ExtraPaydayWeeks() int {
+	switch p.Frequency {
+	case FourWeekly, Fortnightly, Weekly:
+		if p.ExtraPayday {
+			return weeksPerYear / p.Periods()
+		}
+	}
+
+	return 0
+}
+
+// Validate returns an error if the Frequency or working pattern is invalid.
+func (p PayPeriod) Validate() error {
+	if p.PerYear() <= 0 {
+		return fmt.Errorf("the requested %s pay period does not exist", p.Frequency)
+	}
+	if p.HoursPerWeek < 0 || p.HoursPerWeek > 168 || p.DaysPerWeek < 0 || p.DaysPerWeek > 7 || p.WeeksPerYear < 0 || p.WeeksPerYear > 53 {
+		return fmt.Errorf("the working pattern of the %s pay period is invalid", p.Frequency)
+	}
+
+	return nil
+}
+
+// ToAnnual returns the yearly amount of pay received for the period.
+func (p PayPeriod) ToAnnual(m Money) Money {
+	return m.Mul(p.PerYear())
+}
+
+// FromAnnual returns the amount of a yearly pay received for the period.
+func (p PayPeriod) FromAnnual(m Money) Money {
+	return m.Div(p.PerYear())
+}
+
+// Label returns the name of the period as an adverb, e.g. Monthly.
+func (p PayPeriod) Label() string {
+	switch p.Frequency {
+	case Annually:
+		return "Yearly"
+	case Monthly:
+		return "Monthly"
+	case FourWeekly:
+		return "4-Weekly"
+	case Fortnightly:
+		return "Fortnightly"
+	case Weekly:
+		return "Weekly"
+	case Daily:
+		return "Daily"
+	case Hourly:
+		return "Hourly"
+	default:
+		return string(p.Frequency)
+	}
+}
@@ -0,0 +1,137 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+)
+
+func TestPayPeriod(t *testing.T) {
+	tests := map[string]struct {
+		period   PayPeriod
+		pay      Money
+		expected Money
+	}{
+		"Annually": {
+			period:   PayPeriod{Frequency: Annually},
+			pay:      money.New(45000),
+			expected: money.New(45000),
+		},
+		"Monthly": {
+			period:   PayPeriod{Frequency: Monthly},
+			pay:      money.New(3000),
+			expected: money.New(36000),
+		},
+		"FourWeekly": {
+			period:   PayPeriod{Frequency: FourWeekly},
+			pay:      money.New(2000),
+			expected: money.New(26000),
+		},
+		"FourWeeklyWeek56": {
+			period:   PayPeriod{Frequency: FourWeekly, ExtraPayday: true},
+			pay:      money.New(2000),
+			expected: money.New(28000),
+		},
+		"Fortnightly": {
+			period:   PayPeriod{Frequency: Fortnightly},
+			pay:      money.New(1000),
+			expected: money.New(26000),
+		},
+		"Weekly": {
+			period:   PayPeriod{Frequency: Weekly},
+			pay:      money.New(500),
+			expected: money.New(26000),
+		},
+		"WeeklyWeek53": {
+			period:   PayPeriod{Frequency: Weekly, ExtraPayday: true},
+			pay:      money.New(500),
+			expected: money.New(26500),
+		},
+		"Daily": {
+			period:   PayPeriod{Frequency: Daily, DaysPerWeek: 4, WeeksPerYear: 46},
+			pay:      money.New(400),
+			expected: money.New(73600),
+		},
+		"HourlyDefaultPattern": {
+			period:   PayPeriod{Frequency: Hourly},
+			pay:      money.New(20),
+			expected: money.New(39000),
+		},
+		"Hourly": {
+			period:   PayPeriod{Frequency: Hourly, HoursPerWeek: 40, WeeksPerYear: 48},
+			pay:      money.New(15.5),
+			expected: money.New(29760),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			annual := test.period.ToAnnual(test.pay)
+			pay := test.period.FromAnnual(annual)
+
+			if annual != test.expected || pay != test.pay {
+				t.Errorf("got {Annual: %v, Pay: %v}, want {Annual: %v, Pay: %v}", annual, pay, test.expected, test.pay)
+			}
+		})
+	}
+
+	tests_fail := map[string]struct {
+		period PayPeriod
+	}{
+		"Unknown": {
+			period: PayPeriod{Frequency: "Quarter"},
+		},
+		"TooManyHours": {
+			period: PayPeriod{Frequency: Hourly, HoursPerWeek: 200},
+		},
+		"NegativeWeeks": {
+			period: PayPeriod{Frequency: Daily, WeeksPerYear: -1},
+		},
+	}
+
+	for name, test := range tests_fail {
+		t.Run(name, func(t *testing.T) {
+			if test.period.Validate() == nil {
+				t.Error("an error was expected but not returned")
+			}
+		})
+	}
+}
+
+func TestExtraPaydayWeeks(t *testing.T) {
+	tests := map[string]struct {
+		period   PayPeriod
+		expected int
+	}{
+		"Weekly": {
+			period:   PayPeriod{Frequency: Weekly},
+			expected: 0,
+		},
+		"WeeklyWeek53": {
+			period:   PayPeriod{Frequency: Weekly, ExtraPayday: true},
+			expected: 1,
+		},
+		"FortnightlyWeek54": {
+			period:   PayPeriod{Frequency: Fortnightly, ExtraPayday: true},
+			expected: 2,
+		},
+		"FourWeeklyWeek56": {
+			period:   PayPeriod{Frequency: FourWeekly, ExtraPayday: true},
+			expected: 4,
+		},
+		"Monthly": {
+			period:   PayPeriod{Frequency: Monthly, ExtraPayday: true},
+			expected: 0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := test.period.ExtraPaydayWeeks()
+
+			if actual != test.expected {
+				t.Errorf("got %v, want %v", actual, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package tax
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+)
+
+// Increase of income used to measure the marginal rate, raised to a whole
+// number of steps of the child benefit charge when it applies.
+var marginalStep = money.New(100)
+
+// Highest number of points of a RateCurve.
+const maxCurvePoints = 1000
+
+// RatePoint is the combined rate of income tax, National Insurance and
+// student loans at an income. The effective rate is the share of the
+// income deducted, the marginal rate the share of the next pound.
+type RatePoint struct {
+	Income     Money
+	Deductions Money
+	Effective  float64
+	Marginal   float64
+}
+
+// Cliff is a range of income where the marginal rate is raised by the
+// withdrawal of an allowance or benefit.
+type Cliff struct {
+	Name string
+	From Money
+	To   Money
+}
+
+// RateCurve is a series of RatePoint over a range of income.
+type RateCurve struct {
+	Points []RatePoint
+	Cliffs []Cliff
+}
+
+// Deductions of a breakdown counted towards the combined rate.
+func (b IncomeTaxBreakdown) deductions() Money {
+	return b.Taxed + b.NationalInsurance + b.Class4NationalInsurance + b.Class2NationalInsurance + b.StudentLoan + b.PostgraduateLoan + b.ChildBenefitCharge
+}
+
+// Calculate the effective and marginal rates at a yearly gross income.
+func (t TaxCalculator) CalculateRates(income Money, opts Options) (RatePoint, error) {
+	tax, err := t.CalculateTakeHome(income, opts)
+	if err != nil {
+		return RatePoint{}, err
+	}
+	step := t.marginalStep(opts)
+	next, err := t.CalculateTakeHome(income+step, opts)
+	if err != nil {
+		return RatePoint{}, err
+	}
+
+	p := RatePoint{
+		Income:     income,
+		Deductions: tax.deductions(),
+		Marginal:   float64(next.deductions()-tax.deductions()) / float64(step),
+	}
+	if income > 0 {
+		p.Effective = float64(p.Deductions) / float64(income)
+	}
+
+	return p, nil
+}
+
+// Increase of income measuring the marginal rate, over whole steps of the
+// child benefit charge so that each step is spread over its income.
+func (t TaxCalculator) marginalStep(opts Options) Money {
+	cb := t.ChildBenefitRates
+	if !opts.ChildBenefit.Claimed || opts.ChildBenefit.Children <= 0 || cb.ChargeStep <= 0 {
+		return marginalStep
+	}
+
+	steps := max((marginalStep+cb.ChargeStep-1)/cb.ChargeStep, 1)
+
+	return cb.ChargeStep * steps
+}
+
+// Calculate the effective and marginal rates from an income to another,
+// in steps, and the cliffs found within the range. Incomes smaller than the
+// pension contribution are left out.
+func (t TaxCalculator) CalculateRateCurve(from Money, to Money, step Money, opts Options) (RateCurve, error) {
+	if from < 0 || to <= from || step <= 0 {
+		return RateCurve{}, fmt.Errorf("the range from %s to %s in steps of %s is invalid", from.Format(0), to.Format(0), step.Format(0))
+	}
+	if int64((to-from)/step) >= maxCurvePoints {
+		return RateCurve{}, fmt.Errorf("the range from %s to %s in steps of %s has more than %d points", from.Format(0), to.Format(0), step.Format(0), maxCurvePoints)
+	}
+
+	curve := RateCurve{}
+
+	for income := from; income <= to; income += step {
+		p, err := t.CalculateRates(income, opts)
+		if errors.Is(err, ErrContributionAboveIncome) {
+			continue
+		}
+		if err != nil {
+			return RateCurve{}, err
+		}
+		curve.Points = append(curve.Points, p)
+	}
+
+	cliffs, err := t.cliffs(opts)
+	if err != nil {
+		return RateCurve{}, err
+	}
+
+	for _, c := range cliffs {
+		if c.From < to && c.To > from {
+			curve.Cliffs = append(curve.Cliffs, c)
+		}
+	}
+
+	return curve, nil
+}
+
+// Cliffs of the rules applying to the taxpayer.
+func (t TaxCalculator) cliffs(opts Options) ([]Cliff, error) {
+	var cliffs []Cliff
+
+	rates, ok := t.IncomeTaxRates[opts.Residency]
+	if ok && opts.TaxCode == nil {
+		from, err := t.incomeForAdjustedNetIncome(rates.PersonalAllowanceThreshold, opts)
+		if err != nil {
+			return nil, err
+		}
+		to, err := t.incomeForAdjustedNetIncome(rates.PersonalAllowanceThreshold+rates.PersonalAllowance*2, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		cliffs = append(cliffs, Cliff{
+			Name: "Personal allowance taper",
+			From: from,
+			To:   to,
+		})
+	}
+
+	// The Marriage Allowance is lost once taxed above the basic rate, the
+	// cliff going on until the take home pay is made up.
+	if opts.MarriageAllowance == MarriageAllowanceRecipient && opts.TaxCode == nil {
+		from, err := t.incomeWhere(0, opts, taxedAboveBasicRate)
+		if err != nil {
+			return nil, err
+		}
+		before, err := t.CalculateTakeHome(max(from-penny, 0), opts)
+		if err != nil {
+			return nil, err
+		}
+		to, err := t.incomeWhere(from, opts, func(tax IncomeTaxBreakdown) bool { return tax.TakeHome >= before.TakeHome })
+		if err != nil {
+			return nil, err
+		}
+
+		cliffs = append(cliffs, Cliff{
+			Name: "Marriage Allowance withdrawal",
+			From: from,
+			To:   to,
+		})
+	}
+
+	cb := t.ChildBenefitRates
+	if opts.ChildBenefit.Claimed && opts.ChildBenefit.Children > 0 && cb.ChargeStep > 0 {
+		from, err := t.incomeForAdjustedNetIncome(cb.ChargeThreshold, opts)
+		if err != nil {
+			return nil, err
+		}
+		to, err := t.incomeForAdjustedNetIncome(cb.chargeLimit(), opts)
+		if err != nil {
+			return nil, err
+		}
+
+		cliffs = append(cliffs, Cliff{
+			Name: "High Income Child Benefit Charge",
+			From: from,
+			To:   to,
+		})
+	}
+
+	return cliffs, nil
+}
+
+// Lowest yearly gross income, to the penny, giving an adjusted net income
+// of at least an amount, at most the highest income searched by the solver.
+func (t TaxCalculator) incomeForAdjustedNetIncome(adjustedNetIncome Money, opts Options) (Money, error) {
+	return t.incomeWhere(0, opts, func(tax IncomeTaxBreakdown) bool { return tax.AdjustedNetIncome >= adjustedNetIncome })
+}
+
+// Lowest yearly gross income, to the penny, from an income up and at most
+// the highest income searched by the solver, for which a condition holds
+// on the breakdown, the condition holding for any income above.
+func (t TaxCalculator) incomeWhere(from Money, opts Options, condition func(IncomeTaxBreakdown) bool) (Money, error) {
+	holds := func(income Money) (bool, error) {
+		tax, err := t.CalculateTakeHome(income, opts)
+		return belowContribution(condition(tax), err)
+	}
+
+	ok, err := holds(from)
+	if err != nil || ok {
+		return from, err
+	}
+	ok, err = holds(maxGrossIncome)
+	if err != nil || !ok {
+		return maxGrossIncome, err
+	}
+
+	return bisect(from, maxGrossIncome, holds)
+}
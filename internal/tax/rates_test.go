@@ -0,0 +1,179 @@
+package tax
+
+import (
+	"math"
+	"testing"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+)
+
+func TestCalculateRates(t *testing.T) {
+	tests := map[string]struct {
+		income            Money
+		opts              Options
+		expectedEffective float64
+		expectedMarginal  float64
+	}{
+		"NoTax": {
+			income:            money.New(10000),
+			opts:              Options{Residency: RestOfUK, NICategory: "A"},
+			expectedEffective: 0,
+			expectedMarginal:  0,
+		},
+		"HigherRate": {
+			income:            money.New(63450),
+			opts:              Options{Residency: RestOfUK, NICategory: "A"},
+			expectedEffective: 0.2655,
+			expectedMarginal:  0.42,
+		},
+		"AllowanceTaper": {
+			income:            money.New(110000),
+			opts:              Options{Residency: RestOfUK, NICategory: "A"},
+			expectedEffective: 0.3491,
+			expectedMarginal:  0.62,
+		},
+		"StudentLoan": {
+			income:            money.New(40000),
+			opts:              Options{Residency: RestOfUK, NICategory: "A", StudentLoan: Plan2},
+			expectedEffective: 0.2343,
+			expectedMarginal:  0.39,
+		},
+		"ChildBenefitCharge": {
+			income:            money.New(70100),
+			opts:              Options{Residency: RestOfUK, NICategory: "A", ChildBenefit: ChildBenefit{Children: 2, Claimed: true}},
+			expectedEffective: 0.2959,
+			expectedMarginal:  0.53,
+		},
+		// The marginal rate is measured over whole steps of the charge.
+		"ChildBenefitChargeStep": {
+			income:            money.New(65000),
+			opts:              Options{Residency: RestOfUK, NICategory: "A", ChildBenefit: ChildBenefit{Children: 2, Claimed: true}},
+			expectedEffective: 0.2777,
+			expectedMarginal:  0.53,
+		},
+	}
+
+	tax := TaxCalculator{
+		IncomeTaxRates:         map[Residency]IncomeTaxRates{RestOfUK: taxRates},
+		NationalInsuranceRates: niRates,
+		StudentLoanRates:       studentLoanRates,
+		ChildBenefitRates:      childBenefitRates,
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, err := tax.CalculateRates(test.income, test.opts)
+			if err != nil {
+				t.Fatalf("an unexpected error was returned: %v", err)
+			}
+
+			if math.Abs(actual.Effective-test.expectedEffective) > 0.0001 || math.Abs(actual.Marginal-test.expectedMarginal) > 0.0001 {
+				t.Errorf("got {Effective: %.4f, Marginal: %.4f}, want {Effective: %.4f, Marginal: %.4f}",
+					actual.Effective, actual.Marginal, test.expectedEffective, test.expectedMarginal)
+			}
+		})
+	}
+}
+
+func TestCalculateRateCurve(t *testing.T) {
+	tax := TaxCalculator{
+		IncomeTaxRates:         map[Residency]IncomeTaxRates{RestOfUK: taxRates},
+		NationalInsuranceRates: niRates,
+	}
+	opts := Options{Residency: RestOfUK, NICategory: "A"}
+
+	actual, err := tax.CalculateRateCurve(0, money.New(200000), money.New(10000), opts)
+	if err != nil {
+		t.Fatalf("an unexpected error was returned: %v", err)
+	}
+
+	if len(actual.Points) != 21 || actual.Points[20].Income != money.New(200000) {
+		t.Errorf("got %d points, want 21 points from 0 to 200000", len(actual.Points))
+	}
+
+	if len(actual.Cliffs) != 1 || actual.Cliffs[0].From != money.New(100000) || actual.Cliffs[0].To != money.New(125140) {
+		t.Errorf("got cliffs %v, want the personal allowance taper", actual.Cliffs)
+	}
+
+	actual, _ = tax.CalculateRateCurve(0, money.New(50000), money.New(10000), opts)
+	if len(actual.Cliffs) != 0 {
+		t.Errorf("got cliffs %v, want none outside of the range", actual.Cliffs)
+	}
+
+	// A salary sacrifice lowers the adjusted net income, moving the taper
+	// up by the amount sacrificed, and no income below it is shown.
+	sacrifice := opts
+	sacrifice.Pension = Pension{Scheme: SalarySacrifice, Amount: money.New(10000)}
+	actual, err = tax.CalculateRateCurve(0, money.New(200000), money.New(10000), sacrifice)
+	if err != nil {
+		t.Fatalf("an unexpected error was returned: %v", err)
+	}
+	if len(actual.Points) != 20 || actual.Points[0].Income != money.New(10000) {
+		t.Errorf("got %d points, want 20 points from 10000 to 200000", len(actual.Points))
+	}
+	if len(actual.Cliffs) != 1 || actual.Cliffs[0].From != money.New(110000) || actual.Cliffs[0].To != money.New(135140) {
+		t.Errorf("got cliffs %v, want the personal allowance taper from 110000 to 135140", actual.Cliffs)
+	}
+
+	// The Marriage Allowance is lost above the basic rate band, the cliff
+	// going on until the £252 lost is made up.
+	tax.AllowanceRates = allowanceRates
+	marriage := opts
+	marriage.MarriageAllowance = MarriageAllowanceRecipient
+	actual, _ = tax.CalculateRateCurve(0, money.New(100000), money.New(10000), marriage)
+	if len(actual.Cliffs) != 1 || actual.Cliffs[0].From != money.New(50270.01) || actual.Cliffs[0].To != money.New(50706.42) {
+		t.Errorf("got cliffs %v, want the Marriage Allowance withdrawal from 50270.01 to 50706.42", actual.Cliffs)
+	}
+
+	tax.ChildBenefitRates = childBenefitRates
+	opts.ChildBenefit = ChildBenefit{Children: 1, Claimed: true}
+	actual, _ = tax.CalculateRateCurve(0, money.New(100000), money.New(10000), opts)
+	if len(actual.Cliffs) != 1 || actual.Cliffs[0].From != money.New(60000) || actual.Cliffs[0].To != money.New(80000) {
+		t.Errorf("got cliffs %v, want the High Income Child Benefit Charge", actual.Cliffs)
+	}
+	for _, p := range actual.Points {
+		if p.Income >= money.New(60000) && p.Income < money.New(80000) && p.Marginal < 0.45 {
+			t.Errorf("got a marginal rate of %.4f at %v, want the charge within it", p.Marginal, p.Income)
+		}
+	}
+
+	// A net pay pension lowers the adjusted net income, moving the charge
+	// up by the amount contributed.
+	opts.Pension = Pension{Scheme: NetPay, Amount: money.New(8000)}
+	actual, _ = tax.CalculateRateCurve(0, money.New(100000), money.New(10000), opts)
+	if len(actual.Cliffs) != 1 || actual.Cliffs[0].From != money.New(68000) || actual.Cliffs[0].To != money.New(88000) {
+		t.Errorf("got cliffs %v, want the High Income Child Benefit Charge from 68000 to 88000", actual.Cliffs)
+	}
+
+	tests_fail := map[string]struct {
+		from Money
+		to   Money
+		step Money
+	}{
+		"Reversed": {
+			from: money.New(1000),
+			to:   0,
+			step: money.New(100),
+		},
+		"NoStep": {
+			from: 0,
+			to:   money.New(1000),
+			step: 0,
+		},
+		"TooManyPoints": {
+			from: 0,
+			to:   money.New(200000),
+			step: money.New(1),
+		},
+	}
+
+	for name, test := range tests_fail {
+		t.Run(name, func(t *testing.T) {
+			_, err := tax.CalculateRateCurve(test.from, test.to, test.step, opts)
+
+			if err == nil {
+				t.Error("an error was expected but not returned")
+			}
+		})
+	}
+}
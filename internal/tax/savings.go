@@ -0,0 +1,51 @@
+package tax
+
+// SavingsRates holds the starting rate for savings band, the personal
+// savings allowance of each band and the ordered schedule of savings
+// rates. The bands are on taxable income and are the UK ones whatever
+// the residency.
+type SavingsRates struct {
+	StartingRateBand          Money
+	PersonalSavingsAllowances map[string]Money
+	Bands                     []Band
+}
+
+// Calculate the tax on taxable savings income stacked on top of the
+// taxable non-savings income. The starting rate band is reduced by the
+// non-savings income and the personal savings allowance depends on the
+// highest band reached by the total taxable income, both are taxed at 0%
+// but use up the bands they fall in.
+// Requirements from https://www.gov.uk/apply-tax-free-interest-on-savings
+func (r SavingsRates) calculateSavingsTax(taxable Money, savings Money, total Money) ([]BandBreakdown, Money) {
+	starting := min(max(r.StartingRateBand-taxable, 0), savings)
+	allowance := min(r.personalSavingsAllowance(total), savings-starting)
+
+	bands, tax := applyBandsFrom(r.Bands, taxable+starting+allowance, savings-starting-allowance)
+	bands = append([]BandBreakdown{
+		{Name: "Starting Rate for Savings", Amount: starting},
+		{Name: "Personal Savings Allowance", Amount: allowance},
+	}, bands...)
+
+	return bands, tax
+}
+
+// Find the personal savings allowance of the highest band reached by a
+// taxable income.
+func (r SavingsRates) personalSavingsAllowance(taxable Money) Money {
+	var allowance Money
+	for i, b := range r.Bands {
+		if i == 0 || taxable > b.Min {
+			allowance = r.PersonalSavingsAllowances[b.Name]
+		}
+	}
+
+	return allowance
+}
+
+// Extend the basic rate band, and every limit above it, by a gross
+// contribution.
+func (r SavingsRates) extendBands(by Money) SavingsRates {
+	r.Bands = extendBandsAfter(r.Bands, basicRateBand, by)
+
+	return r
+}
@@ -0,0 +1,160 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+)
+
+var savingsRates = SavingsRates{
+	StartingRateBand: money.New(5000),
+	PersonalSavingsAllowances: map[string]Money{
+		"Basic":      money.New(1000),
+		"Higher":     money.New(500),
+		"Additional": 0,
+	},
+	Bands: []Band{
+		{Name: "Basic", Min: 0, Max: money.New(37700), Rate: 0.2},
+		{Name: "Higher", Min: money.New(37700), Max: money.New(125140), Rate: 0.4},
+		{Name: "Additional", Min: money.New(125140), Max: 0, Rate: 0.45},
+	},
+}
+
+func TestSavingsTax(t *testing.T) {
+	tests := map[string]struct {
+		taxable  Money
+		savings  Money
+		total    Money
+		expected Money
+	}{
+		"StartingRate": {
+			taxable:  0,
+			savings:  money.New(6000),
+			total:    money.New(6000),
+			expected: 0,
+		},
+		"ReducedStartingRate": {
+			taxable:  money.New(2430),
+			savings:  money.New(6000),
+			total:    money.New(8430),
+			expected: money.New(486),
+		},
+		"HigherRate": {
+			taxable:  money.New(40000),
+			savings:  money.New(2000),
+			total:    money.New(42000),
+			expected: money.New(600),
+		},
+		"PushedIntoHigherRate": {
+			taxable:  money.New(37000),
+			savings:  money.New(2000),
+			total:    money.New(39000),
+			expected: money.New(560),
+		},
+		"AdditionalRate": {
+			taxable:  money.New(130000),
+			savings:  money.New(2000),
+			total:    money.New(132000),
+			expected: money.New(900),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			bands, actual := savingsRates.calculateSavingsTax(test.taxable, test.savings, test.total)
+
+			if actual != test.expected {
+				t.Errorf("got %v, want %v", actual, test.expected)
+			}
+
+			var total Money
+			for _, b := range bands {
+				total += b.Amount
+			}
+			if total != test.savings {
+				t.Errorf("got %v of savings in the bands, want %v", total, test.savings)
+			}
+		})
+	}
+}
+
+func TestTakeHomeSavings(t *testing.T) {
+	tests := map[string]struct {
+		income           Money
+		opts             Options
+		expectedSavings  Money
+		expectedDividend Money
+		expectedTaxable  Money
+	}{
+		"AllowanceLeft": {
+			income:          0,
+			opts:            Options{Residency: RestOfUK, NICategory: "A", Savings: money.New(20000)},
+			expectedSavings: money.New(286),
+			expectedTaxable: money.New(7430),
+		},
+		"SavingsBeforeDividends": {
+			income:           money.New(15000),
+			opts:             Options{Residency: RestOfUK, NICategory: "A", Savings: money.New(6000), Dividends: money.New(2000)},
+			expectedSavings:  money.New(486),
+			expectedDividend: money.New(131.25),
+			expectedTaxable:  money.New(10430),
+		},
+		"ScottishTaxpayer": {
+			income:          money.New(40000),
+			opts:            Options{Residency: Scotland, NICategory: "A", Savings: money.New(2000)},
+			expectedSavings: money.New(200),
+			expectedTaxable: money.New(29430),
+		},
+	}
+
+	noSavingsRates := investmentCalculator
+	noSavingsRates.SavingsRates = SavingsRates{}
+
+	tests_fail := map[string]struct {
+		tax  TaxCalculator
+		opts Options
+	}{
+		"NoSavingsRates": {
+			tax:  noSavingsRates,
+			opts: Options{Residency: RestOfUK, NICategory: "A", Savings: money.New(1000)},
+		},
+		"ResidencyDoesntExist": {
+			tax:  investmentCalculator,
+			opts: Options{Residency: "Atlantis", NICategory: "A", Savings: money.New(1000)},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, err := investmentCalculator.CalculateTakeHome(test.income, test.opts)
+			if err != nil {
+				t.Fatalf("an unexpected error was returned: %v", err)
+			}
+
+			if actual.SavingsTax != test.expectedSavings {
+				t.Errorf("got savings tax %v, want %v", actual.SavingsTax, test.expectedSavings)
+			}
+
+			if actual.DividendTax != test.expectedDividend {
+				t.Errorf("got dividend tax %v, want %v", actual.DividendTax, test.expectedDividend)
+			}
+
+			if actual.Taxable != test.expectedTaxable {
+				t.Errorf("got taxable %v, want %v", actual.Taxable, test.expectedTaxable)
+			}
+
+			if actual.TakeHome != test.income+test.opts.Savings+test.opts.Dividends-actual.Taxed-actual.NationalInsurance {
+				t.Errorf("got take home %v, want the savings and dividends included", actual.TakeHome)
+			}
+		})
+	}
+
+	for name, test := range tests_fail {
+		t.Run(name, func(t *testing.T) {
+			_, err := test.tax.CalculateTakeHome(money.New(30000), test.opts)
+			if err == nil {
+				t.Error("an error was expected but not returned")
+			}
+		})
+	}
+}
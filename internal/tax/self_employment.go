@@ -0,0 +1,131 @@
+package tax
+
+import (
+	"time"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+)
+
+// Liability below which no payments on account are due.
+var paymentOnAccountThreshold = money.New(1000)
+
+// Share of the tax collected at source from which no payments on account
+// are due.
+const collectedAtSourceLimit = 0.8
+
+// Class2Status is how Class 2 National Insurance is treated for a year.
+type Class2Status string
+
+const (
+	Class2NotPaid   Class2Status = "Not paid"
+	Class2Paid      Class2Status = "Paid"
+	Class2Credited  Class2Status = "Credited"
+	Class2Voluntary Class2Status = "Voluntary"
+)
+
+// SelfEmploymentRates holds the trading allowance, the Class 4 bands of
+// yearly profits split on the LPL and UPL, and the Class 2 rates.
+type SelfEmploymentRates struct {
+	TradingAllowance Money
+	Class4           []Band
+	Class2           Class2Rates
+}
+
+// Class2Rates holds the weekly Class 2 rate and the yearly profits from
+// which it is credited and from which it is payable. Class 2 is never
+// payable, only credited, from 2024/25 when the compulsory threshold is 0.
+type Class2Rates struct {
+	WeeklyRate            Money
+	SmallProfitsThreshold Money
+	CompulsoryThreshold   Money
+}
+
+// SelfEmployment describes the yearly trade of a sole trader. The trading
+// allowance, when claimed, replaces the expenses.
+type SelfEmployment struct {
+	Turnover         Money
+	Expenses         Money
+	TradingAllowance bool
+	VoluntaryClass2  bool
+}
+
+// SelfAssessment is the breakdown of a year including self-employment and
+// the payments due to HMRC after it. The liability is the income tax not
+// collected at source and the Class 4 National Insurance, paid half on
+// each payment on account for the next year, Class 2 being paid with the
+// balancing payment.
+type SelfAssessment struct {
+	Breakdown         IncomeTaxBreakdown
+	CollectedAtSource Money
+	Liability         Money
+	PaidOnAccount     Money
+	BalancingPayment  Money
+	FirstPayment      Money
+	SecondPayment     Money
+	JanuaryDue        time.Time
+	JulyDue           time.Time
+}
+
+// Calculate the taxable profit of the trade.
+// Requirements from https://www.gov.uk/guidance/tax-free-allowances-on-property-and-trading-income
+func (s SelfEmployment) calculateProfit(tradingAllowance Money) Money {
+	if s.TradingAllowance {
+		return max(s.Turnover-tradingAllowance, 0)
+	}
+
+	return max(s.Turnover-s.Expenses, 0)
+}
+
+// Calculate the Class 2 National Insurance due on a yearly profit. Below
+// the small profits threshold it is only paid when chosen voluntarily.
+// Requirements from https://www.gov.uk/self-employed-national-insurance-rates
+func (r Class2Rates) calculateClass2(profit Money, voluntary bool) (Money, Class2Status) {
+	switch {
+	case r.CompulsoryThreshold > 0 && profit > r.CompulsoryThreshold:
+		return r.WeeklyRate.Mul(weeksPerYear), Class2Paid
+	case profit >= r.SmallProfitsThreshold:
+		return 0, Class2Credited
+	case voluntary:
+		return r.WeeklyRate.Mul(weeksPerYear), Class2Voluntary
+	default:
+		return 0, Class2NotPaid
+	}
+}
+
+// Calculate the breakdown of a year of employment and self-employment,
+// and the balancing payment and payments on account due the following
+// January and July. The employment income tax is collected at source.
+// Requirements from https://www.gov.uk/understand-self-assessment-bill/payments-on-account
+func (t TaxCalculator) CalculateSelfAssessment(income Money, opts Options, paidOnAccount Money) (SelfAssessment, error) {
+	tax, err := t.CalculateTakeHome(income, opts)
+	if err != nil {
+		return SelfAssessment{}, err
+	}
+
+	employment := opts
+	employment.SelfEmployment = SelfEmployment{}
+	employment.Savings = 0
+	employment.Dividends = 0
+	atSource, err := t.CalculateTakeHome(income, employment)
+	if err != nil {
+		return SelfAssessment{}, err
+	}
+
+	sa := SelfAssessment{
+		Breakdown:         tax,
+		CollectedAtSource: atSource.Taxed,
+		Liability:         max(tax.Taxed+tax.Class4NationalInsurance-atSource.Taxed, 0),
+		PaidOnAccount:     paidOnAccount,
+		JanuaryDue:        time.Date(int(t.Year)+2, time.January, 31, 0, 0, 0, 0, time.UTC),
+		JulyDue:           time.Date(int(t.Year)+2, time.July, 31, 0, 0, 0, 0, time.UTC),
+	}
+	sa.BalancingPayment = sa.Liability + tax.Class2NationalInsurance - paidOnAccount
+
+	total := tax.Taxed + tax.Class4NationalInsurance
+	if sa.Liability >= paymentOnAccountThreshold && float64(sa.CollectedAtSource) < float64(total)*collectedAtSourceLimit {
+		sa.FirstPayment = sa.Liability.Div(2).Round(2)
+		sa.SecondPayment = sa.Liability - sa.FirstPayment
+	}
+
+	return sa, nil
+}
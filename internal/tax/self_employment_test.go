@@ -0,0 +1,198 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+)
+
+var selfEmploymentRates = SelfEmploymentRates{
+	TradingAllowance: money.New(1000),
+	Class4: []Band{
+		{Name: "Up to LPL", Min: 0, Max: money.New(12570), Rate: 0},
+		{Name: "LPL to UPL", Min: money.New(12570), Max: money.New(50270), Rate: 0.06},
+		{Name: "Above UPL", Min: money.New(50270), Max: 0, Rate: 0.02},
+	},
+	Class2: Class2Rates{
+		WeeklyRate:            money.New(3.45),
+		SmallProfitsThreshold: money.New(6725),
+	},
+}
+
+func TestTradingProfit(t *testing.T) {
+	tests := map[string]struct {
+		trade    SelfEmployment
+		expected Money
+	}{
+		"Expenses": {
+			trade:    SelfEmployment{Turnover: money.New(20000), Expenses: money.New(4000)},
+			expected: money.New(16000),
+		},
+		"TradingAllowance": {
+			trade:    SelfEmployment{Turnover: money.New(20000), Expenses: money.New(4000), TradingAllowance: true},
+			expected: money.New(19000),
+		},
+		"WithinTradingAllowance": {
+			trade:    SelfEmployment{Turnover: money.New(800), TradingAllowance: true},
+			expected: 0,
+		},
+		"Loss": {
+			trade:    SelfEmployment{Turnover: money.New(2000), Expenses: money.New(3000)},
+			expected: 0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := test.trade.calculateProfit(selfEmploymentRates.TradingAllowance)
+
+			if actual != test.expected {
+				t.Errorf("got %v, want %v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestClass2(t *testing.T) {
+	compulsory := selfEmploymentRates.Class2
+	compulsory.CompulsoryThreshold = money.New(12570)
+
+	tests := map[string]struct {
+		rates          Class2Rates
+		profit         Money
+		voluntary      bool
+		expected       Money
+		expectedStatus Class2Status
+	}{
+		"Credited": {
+			rates:          selfEmploymentRates.Class2,
+			profit:         money.New(30000),
+			expected:       0,
+			expectedStatus: Class2Credited,
+		},
+		"Voluntary": {
+			rates:          selfEmploymentRates.Class2,
+			profit:         money.New(5000),
+			voluntary:      true,
+			expected:       money.New(179.40),
+			expectedStatus: Class2Voluntary,
+		},
+		"NotPaid": {
+			rates:          selfEmploymentRates.Class2,
+			profit:         money.New(5000),
+			expected:       0,
+			expectedStatus: Class2NotPaid,
+		},
+		"CompulsoryBefore2024": {
+			rates:          compulsory,
+			profit:         money.New(30000),
+			expected:       money.New(179.40),
+			expectedStatus: Class2Paid,
+		},
+		"CreditedBefore2024": {
+			rates:          compulsory,
+			profit:         money.New(10000),
+			expected:       0,
+			expectedStatus: Class2Credited,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, status := test.rates.calculateClass2(test.profit, test.voluntary)
+
+			if actual != test.expected || status != test.expectedStatus {
+				t.Errorf("got {%v, %s}, want {%v, %s}", actual, status, test.expected, test.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestSelfAssessment(t *testing.T) {
+	tests := map[string]struct {
+		income            Money
+		trade             SelfEmployment
+		paidOnAccount     Money
+		expectedClass4    string
+		expectedLiability string
+		expectedBalancing string
+		expectedPayment   string
+	}{
+		"SelfEmployed": {
+			income:            money.New(10000),
+			trade:             SelfEmployment{Turnover: money.New(60000), Expenses: money.New(5000)},
+			expectedClass4:    "2356.60",
+			expectedLiability: "15788.60",
+			expectedBalancing: "15788.60",
+			expectedPayment:   "7894.30",
+		},
+		"PaidOnAccount": {
+			income:            money.New(10000),
+			trade:             SelfEmployment{Turnover: money.New(60000), Expenses: money.New(5000)},
+			paidOnAccount:     money.New(5000),
+			expectedClass4:    "2356.60",
+			expectedLiability: "15788.60",
+			expectedBalancing: "10788.60",
+			expectedPayment:   "7894.30",
+		},
+		"BelowThreshold": {
+			trade:             SelfEmployment{Turnover: money.New(14000)},
+			expectedClass4:    "85.80",
+			expectedLiability: "371.80",
+			expectedBalancing: "371.80",
+			expectedPayment:   "0.00",
+		},
+		"MostlyCollectedAtSource": {
+			income:            money.New(60000),
+			trade:             SelfEmployment{Turnover: money.New(3000)},
+			expectedClass4:    "0.00",
+			expectedLiability: "1200.00",
+			expectedBalancing: "1200.00",
+			expectedPayment:   "0.00",
+		},
+	}
+
+	tax := TaxCalculator{
+		Year:                   2024,
+		IncomeTaxRates:         map[Residency]IncomeTaxRates{RestOfUK: taxRates},
+		NationalInsuranceRates: niRates,
+		SelfEmploymentRates:    selfEmploymentRates,
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := Options{Residency: RestOfUK, NICategory: "A", SelfEmployment: test.trade}
+
+			actual, err := tax.CalculateSelfAssessment(test.income, opts, test.paidOnAccount)
+			if err != nil {
+				t.Fatalf("an unexpected error was returned: %v", err)
+			}
+
+			class4 := actual.Breakdown.Class4NationalInsurance.Format(2)
+			liability := actual.Liability.Format(2)
+			balancing := actual.BalancingPayment.Format(2)
+			payment := actual.FirstPayment.Format(2)
+
+			if class4 != test.expectedClass4 || liability != test.expectedLiability || balancing != test.expectedBalancing || payment != test.expectedPayment {
+				t.Errorf("got {Class4: %s, Liability: %s, BalancingPayment: %s, FirstPayment: %s}, want {Class4: %s, Liability: %s, BalancingPayment: %s, FirstPayment: %s}",
+					class4, liability, balancing, payment, test.expectedClass4, test.expectedLiability, test.expectedBalancing, test.expectedPayment)
+			}
+
+			if actual.FirstPayment+actual.SecondPayment != 0 && actual.FirstPayment+actual.SecondPayment != actual.Liability {
+				t.Errorf("got payments on account of %v and %v, want the liability split in two", actual.FirstPayment, actual.SecondPayment)
+			}
+
+			if actual.JanuaryDue.Year() != 2026 || actual.JulyDue.Year() != 2026 {
+				t.Errorf("got payments due on %v and %v, want January and July 2026", actual.JanuaryDue, actual.JulyDue)
+			}
+		})
+	}
+
+	_, err := TaxCalculator{
+		IncomeTaxRates:         map[Residency]IncomeTaxRates{RestOfUK: taxRates},
+		NationalInsuranceRates: niRates,
+	}.CalculateTakeHome(0, Options{Residency: RestOfUK, NICategory: "A", SelfEmployment: SelfEmployment{Turnover: money.New(1000)}})
+	if err == nil {
+		t.Error("an error was expected but not returned")
+	}
+}
@@ -0,0 +1,180 @@
+package tax
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+)
+
+// Smallest step of the gross income searched by the solver.
+var penny = money.New(0.01)
+
+// Highest yearly gross income searched by the solver.
+var maxGrossIncome = money.New(100000000)
+
+// ErrUnreachable is returned when no gross income gives the take home pay.
+var ErrUnreachable = errors.New("the take home pay cannot be reached")
+
+// Calculate the gross income of a period giving a take home pay for the
+// same period, to the penny, and return the breakdown of that income.
+// The take home pay increases with the gross income, including within the
+// personal allowance taper where the marginal rate reaches 60%, other than
+// where it drops: at each step of the High Income Child Benefit Charge and
+// where the recipient of the Marriage Allowance is taxed above the basic
+// rate. The take home pay is searched by bisection between the drops,
+// from the lowest, so the lowest matching gross income is found.
+func (t TaxCalculator) CalculateGross(takeHome Money, period PayPeriod, opts Options) (IncomeTaxBreakdown, error) {
+	target := takeHome.Round(2)
+
+	net := func(gross Money) (IncomeTaxBreakdown, Money, error) {
+		tax, err := t.CalculateTakeHome(period.ToAnnual(gross), opts)
+		return tax, period.FromAnnual(tax.TakeHome).Round(2), err
+	}
+	reaches := func(gross Money) (bool, error) {
+		_, n, err := net(gross)
+		return belowContribution(n >= target, err)
+	}
+
+	if target <= 0 {
+		tax, _, err := net(0)
+		return tax, err
+	}
+
+	lo, hi := Money(0), target
+	for {
+		ok, err := reaches(hi)
+		if err != nil {
+			return IncomeTaxBreakdown{}, err
+		}
+		if ok {
+			break
+		}
+		if period.ToAnnual(hi) > maxGrossIncome {
+			return IncomeTaxBreakdown{}, fmt.Errorf("%w: %s", ErrUnreachable, target.Format(2))
+		}
+		lo, hi = hi, hi*2
+	}
+
+	// The target may be reached just below a drop and lost above it, so
+	// each section ending below a drop is searched first, from no income.
+	drops, err := t.takeHomeDrops(hi, period, opts)
+	if err != nil {
+		return IncomeTaxBreakdown{}, err
+	}
+	if len(drops) > 0 {
+		lo = 0
+	}
+	for _, drop := range drops {
+		if drop-penny <= lo {
+			continue
+		}
+
+		ok, err := reaches(drop - penny)
+		if err != nil {
+			return IncomeTaxBreakdown{}, err
+		}
+		if ok {
+			hi = drop - penny
+			break
+		}
+		lo = drop - penny
+	}
+
+	hi, err = bisect(lo, hi, reaches)
+	if err != nil {
+		return IncomeTaxBreakdown{}, err
+	}
+
+	tax, _, err := net(hi)
+
+	return tax, err
+}
+
+// Conditions on a breakdown, each holding from the gross income where the
+// take home pay drops: the recipient of the Marriage Allowance taxed above
+// the basic rate and each step of the High Income Child Benefit Charge.
+func (t TaxCalculator) dropConditions(opts Options) []func(IncomeTaxBreakdown) bool {
+	var conditions []func(IncomeTaxBreakdown) bool
+
+	if opts.MarriageAllowance == MarriageAllowanceRecipient && opts.TaxCode == nil {
+		conditions = append(conditions, taxedAboveBasicRate)
+	}
+
+	cb := t.ChildBenefitRates
+	if opts.ChildBenefit.Claimed && opts.ChildBenefit.Children > 0 && cb.ChargeStep > 0 {
+		for step := 1; step <= chargeSteps; step++ {
+			ani := cb.ChargeThreshold + cb.ChargeStep*Money(step)
+			conditions = append(conditions, func(tax IncomeTaxBreakdown) bool { return tax.AdjustedNetIncome >= ani })
+		}
+	}
+
+	return conditions
+}
+
+// Gross incomes of a period, up to a highest one, where the take home pay
+// drops, in increasing order.
+func (t TaxCalculator) takeHomeDrops(hi Money, period PayPeriod, opts Options) ([]Money, error) {
+	var drops []Money
+
+	for _, condition := range t.dropConditions(opts) {
+		holds := func(gross Money) (bool, error) {
+			tax, err := t.CalculateTakeHome(period.ToAnnual(gross), opts)
+			return belowContribution(condition(tax), err)
+		}
+
+		ok, err := holds(hi)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		drop, err := bisect(0, hi, holds)
+		if err != nil {
+			return nil, err
+		}
+		drops = append(drops, drop)
+	}
+
+	slices.Sort(drops)
+
+	return drops, nil
+}
+
+// A condition searched by income does not hold below the income paying for
+// the pension contribution, rather than failing.
+func belowContribution(ok bool, err error) (bool, error) {
+	if errors.Is(err, ErrContributionAboveIncome) {
+		return false, nil
+	}
+
+	return ok, err
+}
+
+// Find by bisection the lowest amount, to the penny, within (lo, hi] for
+// which a condition holds, the condition holding at hi and for any amount
+// above one for which it holds.
+func bisect(lo Money, hi Money, holds func(Money) (bool, error)) (Money, error) {
+	for hi-lo > penny {
+		mid := (lo + (hi-lo)/2).RoundDown(2)
+		if mid <= lo {
+			mid = lo + penny
+		}
+
+		ok, err := holds(mid)
+		if err != nil {
+			return 0, err
+		}
+
+		if ok {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+
+	return hi, nil
+}
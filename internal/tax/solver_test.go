@@ -0,0 +1,123 @@
+package tax
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+)
+
+func TestCalculateGross(t *testing.T) {
+	tests := map[string]struct {
+		takeHome Money
+		period   PayPeriod
+		opts     Options
+		gross    Money
+	}{
+		"NoTax": {
+			takeHome: money.New(7543),
+			period:   PayPeriod{Frequency: Annually},
+			opts:     Options{Residency: RestOfUK, NICategory: "A"},
+		},
+		"HigherRate": {
+			takeHome: money.New(46604.68),
+			period:   PayPeriod{Frequency: Annually},
+			opts:     Options{Residency: RestOfUK, NICategory: "A"},
+		},
+		"Monthly": {
+			takeHome: money.New(3000),
+			period:   PayPeriod{Frequency: Monthly},
+			opts:     Options{Residency: RestOfUK, NICategory: "A"},
+		},
+		"AllowanceTaper": {
+			takeHome: money.New(5500),
+			period:   PayPeriod{Frequency: Monthly},
+			opts:     Options{Residency: RestOfUK, NICategory: "A"},
+		},
+		"StudentLoanAndPension": {
+			takeHome: money.New(3000),
+			period:   PayPeriod{Frequency: Monthly},
+			opts: Options{
+				Residency:   Scotland,
+				NICategory:  "A",
+				StudentLoan: Plan2,
+				Pension:     Pension{Scheme: SalarySacrifice, Rate: 0.05},
+			},
+		},
+		// The take home pay is reached just below the first step of the
+		// charge, lost above it and reached again at a higher income.
+		"ChildBenefitCharge": {
+			takeHome: money.New(44702.74),
+			period:   PayPeriod{Frequency: Annually},
+			opts: Options{
+				Residency:    RestOfUK,
+				NICategory:   "A",
+				ChildBenefit: ChildBenefit{Children: 3, Claimed: true},
+			},
+			gross: money.New(60170.79),
+		},
+		// The take home pay is reached within the basic rate band, lost
+		// with the Marriage Allowance above it and reached again later.
+		"MarriageAllowance": {
+			takeHome: money.New(39100),
+			period:   PayPeriod{Frequency: Annually},
+			opts: Options{
+				Residency:         RestOfUK,
+				NICategory:        "A",
+				MarriageAllowance: MarriageAllowanceRecipient,
+			},
+			gross: money.New(50108),
+		},
+		// No gross income below the contribution is searched.
+		"PensionAboveTakeHome": {
+			takeHome: money.New(5000),
+			period:   PayPeriod{Frequency: Annually},
+			opts: Options{
+				Residency:  RestOfUK,
+				NICategory: "A",
+				Pension:    Pension{Scheme: SalarySacrifice, Amount: money.New(20000)},
+			},
+			gross: money.New(25000),
+		},
+	}
+
+	tax := TaxCalculator{
+		IncomeTaxRates: map[Residency]IncomeTaxRates{
+			RestOfUK: taxRates,
+			Scotland: scottishTaxRates,
+		},
+		NationalInsuranceRates: niRates,
+		StudentLoanRates:       studentLoanRates,
+		ChildBenefitRates:      childBenefitRates,
+		AllowanceRates:         allowanceRates,
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, err := tax.CalculateGross(test.takeHome, test.period, test.opts)
+			if err != nil {
+				t.Fatalf("an unexpected error was returned: %v", err)
+			}
+
+			takeHome := test.period.FromAnnual(actual.TakeHome).Round(2)
+			if takeHome != test.takeHome {
+				t.Errorf("got take home %v, want %v", takeHome, test.takeHome)
+			}
+
+			gross := test.period.FromAnnual(actual.GrossIncome)
+			if test.gross != 0 && gross != test.gross {
+				t.Errorf("got gross income %v, want %v", gross, test.gross)
+			}
+
+			lower, _ := tax.CalculateTakeHome(test.period.ToAnnual(gross-penny), test.opts)
+			if test.period.FromAnnual(lower.TakeHome).Round(2) >= test.takeHome {
+				t.Errorf("got gross income %v, a lower gross income gives the same take home", gross)
+			}
+		})
+	}
+
+	_, err := tax.CalculateGross(money.New(3000), PayPeriod{Frequency: Monthly}, Options{Residency: RestOfUK, NICategory: "A", Pension: Pension{Scheme: NetPay, Rate: 1}})
+	if !errors.Is(err, ErrUnreachable) {
+		t.Errorf("got %v, want %v", err, ErrUnreachable)
+	}
+}
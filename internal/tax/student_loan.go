@@ -0,0 +1,39 @@
+package tax
+
+import (
+	"fmt"
+)
+
+// StudentLoanPlan identifies an undergraduate student loan repayment plan.
+type StudentLoanPlan string
+
+const (
+	NoStudentLoan StudentLoanPlan = ""
+	Plan1         StudentLoanPlan = "Plan 1"
+	Plan2         StudentLoanPlan = "Plan 2"
+	Plan4         StudentLoanPlan = "Plan 4"
+	Plan5         StudentLoanPlan = "Plan 5"
+	Postgraduate  StudentLoanPlan = "Postgraduate"
+)
+
+// StudentLoanRates holds the yearly repayment threshold of a plan and
+// the rate applied to income above it.
+type StudentLoanRates struct {
+	Threshold Money
+	Rate      float64
+}
+
+// Calculate the yearly repayment due on a student loan plan.
+// Requirements from https://www.gov.uk/repaying-your-student-loan/what-you-pay
+func (t TaxCalculator) calculateStudentLoan(income Money, plan StudentLoanPlan) (Money, error) {
+	if plan == NoStudentLoan {
+		return 0, nil
+	}
+
+	rates, ok := t.StudentLoanRates[plan]
+	if !ok {
+		return 0, fmt.Errorf("the requested %s student loan plan does not exist", plan)
+	}
+
+	return max(income-rates.Threshold, 0).Mul(rates.Rate), nil
+}
@@ -0,0 +1,78 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+)
+
+var studentLoanRates = map[StudentLoanPlan]StudentLoanRates{
+	Plan1:        {Threshold: money.New(24990), Rate: 0.09},
+	Plan2:        {Threshold: money.New(27295), Rate: 0.09},
+	Plan4:        {Threshold: money.New(31395), Rate: 0.09},
+	Plan5:        {Threshold: money.New(25000), Rate: 0.09},
+	Postgraduate: {Threshold: money.New(21000), Rate: 0.06},
+}
+
+func TestStudentLoan(t *testing.T) {
+	tests := map[string]struct {
+		income   Money
+		plan     StudentLoanPlan
+		expected Money
+	}{
+		"NoPlan": {
+			income:   money.New(45000),
+			plan:     NoStudentLoan,
+			expected: 0,
+		},
+		"BelowThreshold": {
+			income:   money.New(25000),
+			plan:     Plan2,
+			expected: 0,
+		},
+		"Plan1": {
+			income:   money.New(45000),
+			plan:     Plan1,
+			expected: money.New(1800.9),
+		},
+		"Plan2": {
+			income:   money.New(45000),
+			plan:     Plan2,
+			expected: money.New(1593.45),
+		},
+		"Plan4": {
+			income:   money.New(45000),
+			plan:     Plan4,
+			expected: money.New(1224.45),
+		},
+		"Plan5": {
+			income:   money.New(45000),
+			plan:     Plan5,
+			expected: money.New(1800),
+		},
+		"Postgraduate": {
+			income:   money.New(45000),
+			plan:     Postgraduate,
+			expected: money.New(1440),
+		},
+	}
+
+	tax := TaxCalculator{
+		StudentLoanRates: studentLoanRates,
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, _ := tax.calculateStudentLoan(test.income, test.plan)
+
+			if actual != test.expected {
+				t.Errorf("got %v, want %v", actual, test.expected)
+			}
+		})
+	}
+
+	_, err := tax.calculateStudentLoan(money.New(45000), "Plan 3")
+	if err == nil {
+		t.Error("an error was expected but not returned")
+	}
+}
@@ -1,105 +1,553 @@
 package tax
 
 import (
+	"encoding/json"
 	"fmt"
+	"slices"
 
 	"github.com/vfc2/tax-calculator/internal/money"
+	"github.com/vfc2/tax-calculator/internal/taxcode"
 )
 
 type Money = money.Money
 
+// Residency determines which income tax regime applies to the taxpayer.
+type Residency string
+
+const (
+	RestOfUK Residency = "rUK"
+	Scotland Residency = "Scotland"
+	Wales    Residency = "Wales"
+)
+
+// Band is a slice of income taxed at a single rate. A Max of 0 means
+// the band is open-ended.
 type Band struct {
+	Name string
 	Min  Money
 	Max  Money
 	Rate float64
 }
 
+// BandBreakdown is the amount that fell within a Band and the tax due on it.
+type BandBreakdown struct {
+	Name   string
+	Rate   float64
+	Amount Money
+	Tax    Money
+}
+
+// IncomeTaxRates holds the personal allowance and an ordered schedule
+// of bands applied to taxable income, i.e. income after the allowance.
 type IncomeTaxRates struct {
 	PersonalAllowance          Money
 	PersonalAllowanceThreshold Money
-	Basic                      Band
-	Higher                     Band
-	Additional                 Band
+	ReliefAtSourceRate         float64
+	Bands                      []Band
 }
 
+// NationalInsuranceRates holds ordered schedules of weekly earnings
+// thresholds, each band carrying its own rate. Employee bands are split
+// on the LEL, PT and UEL, Employer bands on the ST and the relief
+// thresholds of the category (FUST, UST, AUST, VUST).
 type NationalInsuranceRates struct {
-	Band1 Band
-	Band2 Band
-	Band3 Band
+	Employee []Band
+	Employer []Band
 }
 
 type IncomeTaxBreakdown struct {
-	GrossIncome       Money
-	BasicRate         Money
-	HigherRate        Money
-	AdditionalRate    Money
-	Taxable           Money
-	Taxed             Money
-	NationalInsurance Money
-	TakeHome          Money
+	Residency                 Residency
+	TaxCode                   string
+	Allowance                 Money
+	GrossIncome               Money
+	Bands                     []BandBreakdown
+	Taxable                   Money
+	Taxed                     Money
+	TaxReduction              Money
+	TaxReducers               []BandBreakdown
+	TradingProfit             Money
+	Class4NationalInsurance   Money
+	Class4Bands               []BandBreakdown
+	Class2NationalInsurance   Money
+	Class2Status              Class2Status
+	Savings                   Money
+	SavingsBands              []BandBreakdown
+	SavingsTax                Money
+	Dividends                 Money
+	DividendBands             []BandBreakdown
+	DividendTax               Money
+	NationalInsurance         Money
+	NationalInsuranceBands    []BandBreakdown
+	NationalInsurancePeriods  []NationalInsurancePeriod
+	EmployerNationalInsurance Money
+	EmploymentCost            Money
+	StudentLoan               Money
+	PostgraduateLoan          Money
+	PensionContribution       Money
+	PensionTaxRelief          Money
+	GiftAid                   Money
+	HigherRateRelief          Money
+	Benefits                  []Benefit
+	BenefitsInKind            Money
+	Class1ANationalInsurance  Money
+	AdjustedNetIncome         Money
+	AllowanceAdjustments      []AllowanceAdjustment
+	// MarriageAllowanceIneligible is set when the Marriage Allowance is
+	// claimed but not given, the income of the transferor being above the
+	// personal allowance or the recipient being taxed above the basic rate.
+	MarriageAllowanceIneligible bool
+	ChildBenefit                Money
+	ChildBenefitCharge          Money
+	TakeHome                    Money
 }
 
+// TaxCalculator holds the rates of a tax year. NationalInsuranceRates
+// apply from the start of the year, NationalInsuranceChanges from their
+// date, and NationalInsuranceThresholds give the yearly amounts of their
+// weekly thresholds.
 type TaxCalculator struct {
-	IncomeTaxRates         IncomeTaxRates
-	NationalInsuranceRates map[string]NationalInsuranceRates
+	Year                        TaxYear
+	IncomeTaxRates              map[Residency]IncomeTaxRates
+	NationalInsuranceRates      map[string]NationalInsuranceRates
+	NationalInsuranceChanges    []NationalInsuranceChange
+	NationalInsuranceThresholds []NationalInsuranceThreshold
+	// DirectorNationalInsuranceRates are the annual bands published for
+	// directors when the rates change within the tax year.
+	DirectorNationalInsuranceRates map[string]NationalInsuranceRates
+	StudentLoanRates               map[StudentLoanPlan]StudentLoanRates
+	SelfEmploymentRates            SelfEmploymentRates
+	SavingsRates                   SavingsRates
+	DividendRates                  DividendRates
+	CorporationTaxRates            CorporationTaxRates
+	ChildBenefitRates              ChildBenefitRates
+	AllowanceRates                 AllowanceRates
+	BenefitRates                   BenefitRates
+}
+
+// Options describes the circumstances of the taxpayer used in a calculation.
+type Options struct {
+	Residency        Residency
+	NICategory       string
+	StudentLoan      StudentLoanPlan
+	PostgraduateLoan bool
+	Pension          Pension
+	// Director uses the annual earnings period for National Insurance.
+	Director bool
+	// ExtraPaydayWeeks are the weeks of the earnings period of an extra
+	// payday of the year, its National Insurance being due on its own
+	// thresholds.
+	ExtraPaydayWeeks int
+	// SelfEmployment is the trade of the taxpayer as a sole trader, its
+	// profit being taxed with employment income.
+	SelfEmployment SelfEmployment
+	// Savings is the yearly savings interest, taxed after employment
+	// income.
+	Savings Money
+	// Dividends is the yearly dividend income, taxed after employment
+	// income and savings.
+	Dividends Money
+	// TaxCode, when set, replaces the Residency and the personal allowance.
+	TaxCode *taxcode.Code
+	// ChildBenefit, when claimed, is charged back as the adjusted net
+	// income goes above the charge threshold.
+	ChildBenefit ChildBenefit
+	// MarriageAllowance transfers part of the personal allowance to, or
+	// receives it from, a spouse or civil partner.
+	MarriageAllowance MarriageAllowance
+	// MarriedCouplesAllowance is claimed when one of the couple was born
+	// before 6 April 1935.
+	MarriedCouplesAllowance bool
+	// BlindPerson claims the Blind Person's Allowance.
+	BlindPerson bool
+	// JobExpenses are added to the allowance and reduce the adjusted net
+	// income.
+	JobExpenses JobExpenses
+	// GiftAid is the yearly amount donated to charities under Gift Aid,
+	// before the basic rate tax claimed by the charities.
+	GiftAid Money
+	// BenefitsInKind are taxed as pay without employee National Insurance,
+	// the employer paying Class 1A on them.
+	BenefitsInKind BenefitsInKind
 }
 
-// Calculate the National Insurance amount due weekly for Category A.
+// UnmarshalJSON decodes IncomeTaxRates. Configs using the legacy fixed
+// Basic, Higher and Additional bands, expressed on gross income, are
+// migrated to a schedule on taxable income, relief at source being
+// given at the basic rate.
+func (r *IncomeTaxRates) UnmarshalJSON(data []byte) error {
+	type rates IncomeTaxRates
+
+	aux := struct {
+		rates
+		Basic      *Band
+		Higher     *Band
+		Additional *Band
+	}{}
+
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	*r = IncomeTaxRates(aux.rates)
+
+	if len(r.Bands) == 0 && aux.Basic != nil && aux.Higher != nil && aux.Additional != nil {
+		basicLimit := aux.Basic.Max - r.PersonalAllowance
+
+		if r.ReliefAtSourceRate == 0 {
+			r.ReliefAtSourceRate = aux.Basic.Rate
+		}
+
+		r.Bands = []Band{
+			{Name: "Basic", Min: 0, Max: basicLimit, Rate: aux.Basic.Rate},
+			{Name: "Higher", Min: basicLimit, Max: aux.Higher.Max, Rate: aux.Higher.Rate},
+			{Name: "Additional", Min: aux.Higher.Max, Max: 0, Rate: aux.Additional.Rate},
+		}
+	}
+
+	return nil
+}
+
+// Apply an ordered schedule of bands to an amount and return the
+// breakdown of each band and the total due.
+func applyBands(bands []Band, amount Money) ([]BandBreakdown, Money) {
+	breakdown := make([]BandBreakdown, 0, len(bands))
+	var total Money
+
+	for _, b := range bands {
+		upper := amount
+		if b.Max != 0 {
+			upper = min(amount, b.Max)
+		}
+
+		in := max(upper-b.Min, 0)
+		tax := in.Mul(b.Rate)
+
+		breakdown = append(breakdown, BandBreakdown{
+			Name:   b.Name,
+			Rate:   b.Rate,
+			Amount: in,
+			Tax:    tax,
+		})
+		total += tax
+	}
+
+	return breakdown, total
+}
+
+// Calculate the National Insurance amount due weekly for a Category and
+// the earnings falling in each threshold band.
 // Requirements from https://www.gov.uk/national-insurance-rates-letters
-func (t TaxCalculator) calculateNationalInsurance(weekIncome Money, category string) (Money, error) {
+func (t TaxCalculator) calculateNationalInsurance(weekIncome Money, category string) ([]BandBreakdown, Money, error) {
 	cat, ok := t.NationalInsuranceRates[category]
 	if !ok {
-		return 0, fmt.Errorf("the requested %s Category does not exist", category)
+		return nil, 0, fmt.Errorf("the requested %s Category does not exist", category)
 	}
 
-	c := max(weekIncome-cat.Band2.Max, 0)
-	b := max(weekIncome-c-cat.Band1.Max, 0)
+	bands, tax := applyBands(cat.Employee, weekIncome)
 
-	tax := c.Mul(cat.Band3.Rate) + b.Mul(cat.Band2.Rate)
+	return bands, tax, nil
+}
+
+// Calculate the secondary Class 1 National Insurance due weekly by the
+// employer for a Category.
+// Requirements from https://www.gov.uk/guidance/rates-and-thresholds-for-employers-2024-to-2025
+func (t TaxCalculator) calculateEmployerNationalInsurance(weekIncome Money, category string) (Money, error) {
+	cat, ok := t.NationalInsuranceRates[category]
+	if !ok {
+		return 0, fmt.Errorf("the requested %s Category does not exist", category)
+	}
+
+	_, tax := applyBands(cat.Employer, weekIncome)
 
 	return tax, nil
 }
 
-// Calculate the Taxable Income of yearly gross income.
-// Requirements from https://www.gov.uk/income-tax-rates
-func (t TaxCalculator) calculateIncomeTax(income Money, allowance Money) IncomeTaxBreakdown {
-	hrLimit := t.IncomeTaxRates.Higher.Min + (allowance - t.IncomeTaxRates.PersonalAllowance)
+// Scale a breakdown of bands by a number of periods.
+func scaleBands(bands []BandBreakdown, periods float64) []BandBreakdown {
+	scaled := make([]BandBreakdown, len(bands))
+
+	for i, b := range bands {
+		b.Amount = b.Amount.Mul(periods)
+		b.Tax = b.Tax.Mul(periods)
+		scaled[i] = b
+	}
 
-	ar := max(income-t.IncomeTaxRates.Additional.Min, 0)
-	hr := max(income-ar-hrLimit, 0)
-	br := max(income-ar-hr-allowance, 0)
+	return scaled
+}
 
-	tax := ar.Mul(t.IncomeTaxRates.Additional.Rate) + hr.Mul(t.IncomeTaxRates.Higher.Rate) + br.Mul(t.IncomeTaxRates.Basic.Rate)
+// Calculate the Taxable Income of yearly gross income. The tax is reduced
+// by the tax reducer, down to 0.
+// Requirements from https://www.gov.uk/income-tax-rates
+// and https://www.gov.uk/scottish-income-tax
+func (r IncomeTaxRates) calculateIncomeTax(income Money, allowance Money, reducer Money) IncomeTaxBreakdown {
+	taxable := max(income-allowance, 0)
+	bands, tax := applyBands(r.Bands, taxable)
+	reduction := min(max(reducer, 0), tax)
 
 	return IncomeTaxBreakdown{
-		GrossIncome:    income,
-		BasicRate:      br.Mul(t.IncomeTaxRates.Basic.Rate),
-		HigherRate:     hr.Mul(t.IncomeTaxRates.Higher.Rate),
-		AdditionalRate: ar.Mul(t.IncomeTaxRates.Additional.Rate),
-		Taxed:          tax,
-		Taxable:        max(income-allowance, 0),
+		GrossIncome:  income,
+		Bands:        bands,
+		Taxed:        tax - reduction,
+		Taxable:      taxable,
+		TaxReduction: reduction,
+	}
+}
+
+// taxSchedules holds the bands of the income tax, savings and dividends of
+// the taxpayer.
+type taxSchedules struct {
+	income    IncomeTaxRates
+	savings   SavingsRates
+	dividends DividendRates
+}
+
+// Extend the basic rate band of each schedule, and every limit above it,
+// by a gross amount.
+func (s taxSchedules) extendBands(by Money) taxSchedules {
+	return taxSchedules{
+		income:    s.income.extendBands(by),
+		savings:   s.savings.extendBands(by),
+		dividends: s.dividends.extendBands(by),
 	}
 }
 
-// Calculate the Tax Allowance based on a yearly gross income.
+// Calculate the income tax of the taxable pay, then of the savings and the
+// dividends, in that order, using up the allowance left and taxed on top.
+// The tax reducers reduce the tax on income, then on savings and
+// dividends, the Marriage Allowance only when nothing is taxed above the
+// basic rate.
+func (s taxSchedules) calculateTax(taxablePay Money, allowance Money, savingsIncome Money, dividendIncome Money, reducers []BandBreakdown) IncomeTaxBreakdown {
+	tax := s.income.calculateIncomeTax(taxablePay, allowance, 0)
+
+	left := max(allowance-taxablePay, 0)
+	savings := max(savingsIncome-left, 0)
+	left = max(left-savingsIncome, 0)
+	dividends := max(dividendIncome-left, 0)
+
+	tax.SavingsBands, tax.SavingsTax = s.savings.calculateSavingsTax(tax.Taxable, savings, tax.Taxable+savings+dividends)
+	tax.DividendBands, tax.DividendTax = s.dividends.calculateDividendTax(tax.Taxable+savings, dividends)
+	tax.Taxable += savings + dividends
+
+	marriage := func(r BandBreakdown) bool { return r.Name == marriageAllowanceReducer }
+	if taxedAboveBasicRate(tax) && slices.ContainsFunc(reducers, marriage) {
+		reducers = slices.DeleteFunc(slices.Clone(reducers), marriage)
+		tax.MarriageAllowanceIneligible = true
+	}
+
+	taxed := tax.Taxed + tax.SavingsTax + tax.DividendTax
+	tax.TaxReduction = min(max(totalReducers(reducers), 0), taxed)
+	tax.TaxReducers = limitReducers(reducers, tax.TaxReduction)
+	tax.Taxed = taxed - tax.TaxReduction
+
+	return tax
+}
+
+// Calculate the Tax Allowance based on a yearly adjusted net income.
 // Requirements from https://www.gov.uk/income-tax-rates/income-over-100000
-func (t TaxCalculator) calculateTaxAllowance(annumIncome Money) Money {
-	over := max((annumIncome - t.IncomeTaxRates.PersonalAllowanceThreshold).Mul(0.5), 0)
+func (r IncomeTaxRates) calculateTaxAllowance(adjustedNetIncome Money) Money {
+	over := max((adjustedNetIncome - r.PersonalAllowanceThreshold).Mul(0.5), 0)
 
-	return max(t.IncomeTaxRates.PersonalAllowance-over, 0)
+	return max(r.PersonalAllowance-over, 0)
 }
 
-// Calculate the full income tax and return breakdown.
-func (t TaxCalculator) CalculateTakeHome(income Money, niCategory string) (IncomeTaxBreakdown, error) {
-	allowance := t.calculateTaxAllowance(income)
-	ni, err := t.calculateNationalInsurance(income.Div(52), niCategory)
+// Calculate the full income tax and return breakdown. The income tax regime
+// is selected from the residency, National Insurance is UK-wide.
+// Salary sacrifice reduces the pay subject to tax and National Insurance,
+// net pay reduces the taxable pay only and relief at source extends the
+// basic rate band, as Gift Aid donations do once grossed up. All of them
+// reduce the adjusted net income.
+// When a tax code is provided, the allowance and bands are derived from it,
+// including the allowances transferred or claimed and the job expenses.
+// Otherwise the Marriage Allowance and Married Couple's Allowance reduce
+// the allowance of the transferor or the tax of the claimant, and the
+// Blind Person's Allowance and job expenses are added to the allowance.
+// Trading profits are taxed with employment income and pay Class 2 and
+// Class 4 National Insurance instead of Class 1.
+// Savings then dividends use up the allowance left by employment income
+// and are taxed on top of it, in that order, at their own rates.
+// The child benefit claimed is charged back on the adjusted net income.
+// Benefits in kind are taxed with the pay, those not payrolled being taxed
+// through the tax code instead when one is provided.
+func (t TaxCalculator) CalculateTakeHome(income Money, opts Options) (IncomeTaxBreakdown, error) {
+	if opts.TaxCode != nil {
+		opts.Residency = residencyOf(opts.TaxCode.Country)
+	}
+
+	rates, ok := t.IncomeTaxRates[opts.Residency]
+	if !ok {
+		return IncomeTaxBreakdown{}, fmt.Errorf("the requested %s residency does not exist", opts.Residency)
+	}
+
+	contribution, err := opts.Pension.calculateContribution(income)
+	if err != nil {
+		return IncomeTaxBreakdown{}, err
+	}
+
+	profit := opts.SelfEmployment.calculateProfit(t.SelfEmploymentRates.TradingAllowance)
+	if profit > 0 && len(t.SelfEmploymentRates.Class4) == 0 {
+		return IncomeTaxBreakdown{}, fmt.Errorf("the self-employment rates are not available")
+	}
+	if opts.Savings > 0 && len(t.SavingsRates.Bands) == 0 {
+		return IncomeTaxBreakdown{}, fmt.Errorf("the savings rates are not available")
+	}
+	if opts.Dividends > 0 && len(t.DividendRates.Bands) == 0 {
+		return IncomeTaxBreakdown{}, fmt.Errorf("the dividend rates are not available")
+	}
+	if opts.ChildBenefit.Claimed && opts.ChildBenefit.Children > 0 && t.ChildBenefitRates.ChargeStep == 0 {
+		return IncomeTaxBreakdown{}, fmt.Errorf("the child benefit rates are not available")
+	}
+
+	if opts.TaxCode != nil && (opts.MarriageAllowance != NoMarriageAllowance || opts.MarriedCouplesAllowance) {
+		return IncomeTaxBreakdown{}, fmt.Errorf("the marriage allowances are given by the tax code")
+	}
+
+	if opts.StudentLoan == Postgraduate {
+		return IncomeTaxBreakdown{}, fmt.Errorf("the postgraduate loan is not an undergraduate student loan plan")
+	}
+
+	if opts.GiftAid < 0 {
+		return IncomeTaxBreakdown{}, fmt.Errorf("the Gift Aid donations cannot be negative")
+	}
+
+	benefits, err := t.BenefitRates.calculateBenefits(opts.BenefitsInKind)
+	if err != nil {
+		return IncomeTaxBreakdown{}, err
+	}
+	if len(benefits) > 0 && t.BenefitRates.Class1ARate == 0 {
+		return IncomeTaxBreakdown{}, fmt.Errorf("the benefit rates are not available")
+	}
+
+	// Benefits not payrolled are taxed through the tax code, when one is
+	// provided.
+	var benefitsInKind, taxedBenefits Money
+	for _, b := range benefits {
+		benefitsInKind += b.CashEquivalent
+		if b.Payrolled || opts.TaxCode == nil {
+			taxedBenefits += b.CashEquivalent
+		}
+	}
+
+	pay := income
+	taxablePay := income + profit + taxedBenefits
+	payment := contribution
+	var relief, extension Money
+
+	switch opts.Pension.Scheme {
+	case SalarySacrifice:
+		pay -= contribution
+		taxablePay -= contribution
+	case NetPay:
+		taxablePay -= contribution
+	case ReliefAtSource:
+		relief = contribution.Mul(rates.ReliefAtSourceRate)
+		payment -= relief
+		extension += contribution
+	}
+
+	giftAid := opts.GiftAid.Div(1 - rates.ReliefAtSourceRate).Round(2)
+	extension += giftAid
+
+	var codeAllowance Money
+	if opts.TaxCode != nil {
+		rates, codeAllowance, err = rates.applyTaxCode(*opts.TaxCode)
+		if err != nil {
+			return IncomeTaxBreakdown{}, err
+		}
+	}
+
+	// The allowance, its adjustments and the tax reducers at an adjusted
+	// net income.
+	allowanceAt := func(adjustedNetIncome Money) (Money, []AllowanceAdjustment, []BandBreakdown, error) {
+		if opts.TaxCode != nil {
+			return codeAllowance, []AllowanceAdjustment{{Name: "Tax Code " + opts.TaxCode.String(), Amount: codeAllowance}}, nil, nil
+		}
+
+		transferred, reducers, err := t.AllowanceRates.calculateMarriage(adjustedNetIncome, opts)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		if adjustedNetIncome > rates.PersonalAllowance {
+			transferred = 0
+		}
+		allowance, adjustments, err := t.buildAllowance(rates, adjustedNetIncome, transferred, opts)
+
+		return allowance, adjustments, reducers, err
+	}
+
+	expenses := t.AllowanceRates.calculateJobExpenses(opts.JobExpenses)
+	adjustedNetIncome := max(income+benefitsInKind+profit-contribution-expenses-giftAid, 0) + opts.Savings + opts.Dividends
+	allowance, adjustments, reducers, err := allowanceAt(adjustedNetIncome)
+	if err != nil {
+		return IncomeTaxBreakdown{}, err
+	}
+
+	ni, err := t.calculateYearNationalInsurance(pay, opts.NICategory, opts.Director, opts.ExtraPaydayWeeks)
+	if err != nil {
+		return IncomeTaxBreakdown{}, err
+	}
+	studentLoan, err := t.calculateStudentLoan(pay+profit, opts.StudentLoan)
 	if err != nil {
 		return IncomeTaxBreakdown{}, err
 	}
-	tax := t.calculateIncomeTax(income, allowance)
+	var postgraduateLoan Money
+	if opts.PostgraduateLoan {
+		postgraduateLoan, err = t.calculateStudentLoan(pay+profit, Postgraduate)
+		if err != nil {
+			return IncomeTaxBreakdown{}, err
+		}
+	}
+
+	schedules := taxSchedules{income: rates, savings: t.SavingsRates, dividends: t.DividendRates}
+	tax := schedules.extendBands(extension).calculateTax(taxablePay, allowance, opts.Savings, opts.Dividends, reducers)
 
-	tax.NationalInsurance = ni.Mul(52)
-	tax.TakeHome = income - tax.Taxed - tax.NationalInsurance
+	// The relief above the basic rate is the tax saved by extending the
+	// bands and reducing the adjusted net income.
+	if extension > 0 {
+		allowance, _, reducers, err := allowanceAt(adjustedNetIncome + extension)
+		if err != nil {
+			return IncomeTaxBreakdown{}, err
+		}
+		without := schedules.calculateTax(taxablePay, allowance, opts.Savings, opts.Dividends, reducers)
+		tax.HigherRateRelief = without.Taxed - tax.Taxed
+	}
+
+	tax.TradingProfit = profit
+	tax.Class4Bands, tax.Class4NationalInsurance = applyBands(t.SelfEmploymentRates.Class4, profit)
+	tax.Class2NationalInsurance, tax.Class2Status = t.SelfEmploymentRates.Class2.calculateClass2(profit, opts.SelfEmployment.VoluntaryClass2)
+	tax.Savings = opts.Savings
+	tax.Dividends = opts.Dividends
+
+	tax.AdjustedNetIncome = adjustedNetIncome
+	if opts.ChildBenefit.Claimed {
+		tax.ChildBenefit = t.ChildBenefitRates.calculateChildBenefit(opts.ChildBenefit.Children)
+		tax.ChildBenefitCharge = t.ChildBenefitRates.calculateCharge(adjustedNetIncome, tax.ChildBenefit)
+	}
+
+	tax.Residency = opts.Residency
+	tax.Allowance = allowance
+	tax.AllowanceAdjustments = adjustments
+	if opts.MarriageAllowance == MarriageAllowanceTransferor && adjustedNetIncome > rates.PersonalAllowance {
+		tax.MarriageAllowanceIneligible = true
+	}
+	if opts.TaxCode != nil {
+		tax.TaxCode = opts.TaxCode.String()
+	}
+	tax.GrossIncome = income
+	tax.NationalInsurance = ni.NationalInsurance
+	tax.NationalInsuranceBands = ni.NationalInsuranceBands
+	tax.NationalInsurancePeriods = ni.NationalInsurancePeriods
+	tax.EmployerNationalInsurance = ni.EmployerNationalInsurance
+	tax.Benefits = benefits
+	tax.BenefitsInKind = benefitsInKind
+	tax.Class1ANationalInsurance = benefitsInKind.Mul(t.BenefitRates.Class1ARate).Round(2)
+	tax.EmploymentCost = income + tax.EmployerNationalInsurance + tax.Class1ANationalInsurance
+	tax.StudentLoan = studentLoan
+	tax.PostgraduateLoan = postgraduateLoan
+	tax.PensionContribution = contribution
+	tax.PensionTaxRelief = relief
+	tax.GiftAid = giftAid
+	tax.TakeHome = income + profit + tax.Savings + tax.Dividends - tax.Taxed - tax.NationalInsurance - tax.Class4NationalInsurance - tax.Class2NationalInsurance - tax.StudentLoan - tax.PostgraduateLoan - tax.ChildBenefitCharge - payment - opts.GiftAid
 
 	return tax, nil
 }
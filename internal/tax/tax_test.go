@@ -1,6 +1,9 @@
 package tax
 
 import (
+	"encoding/json"
+	"os"
+	"reflect"
 	"testing"
 
 	"github.com/vfc2/tax-calculator/internal/money"
@@ -9,58 +12,122 @@ import (
 var taxRates = IncomeTaxRates{
 	PersonalAllowance:          money.New(12570),
 	PersonalAllowanceThreshold: money.New(100000),
-	Basic: Band{
-		Min:  money.New(12571),
-		Max:  money.New(50270),
-		Rate: 0.2,
+	ReliefAtSourceRate:         0.2,
+	Bands: []Band{
+		{Name: "Basic", Min: 0, Max: money.New(37700), Rate: 0.2},
+		{Name: "Higher", Min: money.New(37700), Max: money.New(125140), Rate: 0.4},
+		{Name: "Additional", Min: money.New(125140), Max: 0, Rate: 0.45},
 	},
-	Higher: Band{
-		Min:  money.New(50271),
-		Max:  money.New(125140),
-		Rate: 0.4,
-	},
-	Additional: Band{
-		Min:  money.New(125141),
-		Max:  money.New(0),
-		Rate: 0.45,
+}
+
+var scottishTaxRates = IncomeTaxRates{
+	PersonalAllowance:          money.New(12570),
+	PersonalAllowanceThreshold: money.New(100000),
+	ReliefAtSourceRate:         0.2,
+	Bands: []Band{
+		{Name: "Starter", Min: 0, Max: money.New(2306), Rate: 0.19},
+		{Name: "Basic", Min: money.New(2306), Max: money.New(13991), Rate: 0.2},
+		{Name: "Intermediate", Min: money.New(13991), Max: money.New(31092), Rate: 0.21},
+		{Name: "Higher", Min: money.New(31092), Max: money.New(62430), Rate: 0.42},
+		{Name: "Advanced", Min: money.New(62430), Max: money.New(125140), Rate: 0.45},
+		{Name: "Top", Min: money.New(125140), Max: 0, Rate: 0.48},
 	},
 }
 
 var niRates = map[string]NationalInsuranceRates{
 	"A": {
-		Band1: Band{
-			Min:  123000000,
-			Max:  242000000,
-			Rate: 0,
+		Employee: []Band{
+			{Name: "Up to LEL", Min: 0, Max: money.New(123), Rate: 0},
+			{Name: "LEL to PT", Min: money.New(123), Max: money.New(242), Rate: 0},
+			{Name: "PT to UEL", Min: money.New(242), Max: money.New(967), Rate: 0.1},
+			{Name: "Above UEL", Min: money.New(967), Max: 0, Rate: 0.02},
 		},
-		Band2: Band{
-			Min:  242010000,
-			Max:  967000000,
-			Rate: 0.1,
+		Employer: []Band{
+			{Name: "Up to ST", Min: 0, Max: money.New(175), Rate: 0},
+			{Name: "Above ST", Min: money.New(175), Max: 0, Rate: 0.138},
 		},
-		Band3: Band{
-			Min:  967010000,
-			Rate: 0.02,
+	},
+	"M": {
+		Employee: []Band{
+			{Name: "Up to LEL", Min: 0, Max: money.New(123), Rate: 0},
+			{Name: "LEL to PT", Min: money.New(123), Max: money.New(242), Rate: 0},
+			{Name: "PT to UEL", Min: money.New(242), Max: money.New(967), Rate: 0.08},
+			{Name: "Above UEL", Min: money.New(967), Max: 0, Rate: 0.02},
+		},
+		Employer: []Band{
+			{Name: "Up to ST", Min: 0, Max: money.New(175), Rate: 0},
+			{Name: "ST to UST", Min: money.New(175), Max: money.New(967), Rate: 0},
+			{Name: "Above UST", Min: money.New(967), Max: 0, Rate: 0.138},
+		},
+	},
+	"B": {
+		Employee: []Band{
+			{Name: "Up to LEL", Min: 0, Max: money.New(123), Rate: 0},
+			{Name: "LEL to PT", Min: money.New(123), Max: money.New(242), Rate: 0},
+			{Name: "PT to UEL", Min: money.New(242), Max: money.New(967), Rate: 0.0185},
+			{Name: "Above UEL", Min: money.New(967), Max: 0, Rate: 0.02},
 		},
 	},
+	"C": {
+		Employee: []Band{
+			{Name: "Up to LEL", Min: 0, Max: money.New(123), Rate: 0},
+			{Name: "LEL to PT", Min: money.New(123), Max: money.New(242), Rate: 0},
+			{Name: "PT to UEL", Min: money.New(242), Max: money.New(967), Rate: 0},
+			{Name: "Above UEL", Min: money.New(967), Max: 0, Rate: 0},
+		},
+	},
+}
+
+// Calculator of the tests taxing savings and dividends with the pay.
+var investmentCalculator = TaxCalculator{
+	IncomeTaxRates:         map[Residency]IncomeTaxRates{RestOfUK: taxRates, Scotland: scottishTaxRates},
+	NationalInsuranceRates: niRates,
+	SavingsRates:           savingsRates,
+	DividendRates:          dividendRates,
 }
 
 func TestNationalInsurance(t *testing.T) {
 	tests := map[string]struct {
 		income   Money
+		category string
 		expected Money
+		earnings []Money
 	}{
 		"NoTax": {
 			income:   money.New(120),
+			category: "A",
+			expected: 0,
+			earnings: []Money{money.New(120), 0, 0, 0},
+		},
+		"BelowPT": {
+			income:   money.New(200),
+			category: "A",
 			expected: 0,
+			earnings: []Money{money.New(123), money.New(77), 0, 0},
 		},
 		"Mid": {
 			income:   money.New(731),
+			category: "A",
 			expected: money.New(48.9),
+			earnings: []Money{money.New(123), money.New(119), money.New(489), 0},
 		},
 		"High": {
 			income:   money.New(1058),
+			category: "A",
 			expected: money.New(74.32),
+			earnings: []Money{money.New(123), money.New(119), money.New(725), money.New(91)},
+		},
+		"MarriedWomenReducedRate": {
+			income:   money.New(1058),
+			category: "B",
+			expected: money.New(15.2325),
+			earnings: []Money{money.New(123), money.New(119), money.New(725), money.New(91)},
+		},
+		"OverStatePensionAge": {
+			income:   money.New(1058),
+			category: "C",
+			expected: 0,
+			earnings: []Money{money.New(123), money.New(119), money.New(725), money.New(91)},
 		},
 	}
 
@@ -76,23 +143,32 @@ func TestNationalInsurance(t *testing.T) {
 	}
 
 	tax := TaxCalculator{
-		IncomeTaxRates:         taxRates,
+		IncomeTaxRates:         map[Residency]IncomeTaxRates{RestOfUK: taxRates},
 		NationalInsuranceRates: niRates,
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			actual, _ := tax.calculateNationalInsurance(test.income, "A")
+			bands, actual, _ := tax.calculateNationalInsurance(test.income, test.category)
+
+			earnings := make([]Money, len(bands))
+			for i, b := range bands {
+				earnings[i] = b.Amount
+			}
 
 			if actual != test.expected {
 				t.Errorf("got %v, want %v", actual, test.expected)
 			}
+
+			if !reflect.DeepEqual(earnings, test.earnings) {
+				t.Errorf("got earnings %v, want %v", earnings, test.earnings)
+			}
 		})
 	}
 
 	for name, test := range tests_fail {
 		t.Run(name, func(t *testing.T) {
-			actual, err := tax.calculateNationalInsurance(0, test.category)
+			_, actual, err := tax.calculateNationalInsurance(0, test.category)
 
 			if actual != 0 || err == nil {
 				t.Error("an error was expected but not returned")
@@ -101,70 +177,271 @@ func TestNationalInsurance(t *testing.T) {
 	}
 }
 
+func TestEmployerNationalInsurance(t *testing.T) {
+	tests := map[string]struct {
+		income   Money
+		category string
+		expected Money
+	}{
+		"BelowST": {
+			income:   money.New(150),
+			category: "A",
+			expected: 0,
+		},
+		"AboveST": {
+			income:   money.New(731),
+			category: "A",
+			expected: money.New(76.728),
+		},
+		"UnderTwentyOneBelowUST": {
+			income:   money.New(731),
+			category: "M",
+			expected: 0,
+		},
+		"UnderTwentyOneAboveUST": {
+			income:   money.New(1058),
+			category: "M",
+			expected: money.New(12.558),
+		},
+	}
+
+	tax := TaxCalculator{
+		NationalInsuranceRates: niRates,
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, _ := tax.calculateEmployerNationalInsurance(test.income, test.category)
+
+			if actual != test.expected {
+				t.Errorf("got %v, want %v", actual, test.expected)
+			}
+		})
+	}
+
+	_, err := tax.calculateEmployerNationalInsurance(0, "ZZ")
+	if err == nil {
+		t.Error("an error was expected but not returned")
+	}
+}
+
+func TestApplyBands(t *testing.T) {
+	bands := []Band{
+		{Name: "Zero", Min: 0, Max: money.New(100), Rate: 0},
+		{Name: "Low", Min: money.New(100), Max: money.New(200), Rate: 0.1},
+		{Name: "High", Min: money.New(300), Max: 0, Rate: 0.5},
+	}
+
+	tests := map[string]struct {
+		amount   Money
+		expected []Money
+		total    Money
+	}{
+		"Nothing": {
+			amount:   0,
+			expected: []Money{0, 0, 0},
+			total:    0,
+		},
+		"WithinGap": {
+			amount:   money.New(250),
+			expected: []Money{money.New(100), money.New(100), 0},
+			total:    money.New(10),
+		},
+		"OpenEnded": {
+			amount:   money.New(1000),
+			expected: []Money{money.New(100), money.New(100), money.New(700)},
+			total:    money.New(360),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, total := applyBands(bands, test.amount)
+
+			amounts := make([]Money, len(actual))
+			for i, b := range actual {
+				amounts[i] = b.Amount
+			}
+
+			if !reflect.DeepEqual(amounts, test.expected) || total != test.total {
+				t.Errorf("got %v (total %v), want %v (total %v)", amounts, total, test.expected, test.total)
+			}
+		})
+	}
+}
+
 func TestIncomeTax(t *testing.T) {
 	tests := map[string]struct {
+		rates     IncomeTaxRates
 		income    Money
 		allowance Money
-		expected  IncomeTaxBreakdown
+		reducer   Money
+		taxable   Money
+		taxed     Money
+		bands     []Money
 	}{
 		"NoTax": {
+			rates:     taxRates,
 			income:    money.New(7543),
 			allowance: money.New(12570),
-			expected: IncomeTaxBreakdown{
-				GrossIncome: money.New(7543),
-			},
+			bands:     []Money{0, 0, 0},
 		},
 		"BasicRate": {
+			rates:     taxRates,
 			income:    money.New(35000),
 			allowance: money.New(12570),
-			expected: IncomeTaxBreakdown{
-				GrossIncome: money.New(35000),
-				BasicRate:   money.New(4486),
-				Taxable:     money.New(22430),
-				Taxed:       money.New(4486),
-			},
+			taxable:   money.New(22430),
+			taxed:     money.New(4486),
+			bands:     []Money{money.New(4486), 0, 0},
 		},
 		"HigherRate": {
+			rates:     taxRates,
 			income:    money.New(63450),
 			allowance: money.New(12570),
-			expected: IncomeTaxBreakdown{
-				GrossIncome: money.New(63450),
-				BasicRate:   money.New(7540.20),
-				HigherRate:  money.New(5271.60),
-				Taxable:     money.New(50880),
-				Taxed:       money.New(12811.80),
-			},
+			taxable:   money.New(50880),
+			taxed:     money.New(12812),
+			bands:     []Money{money.New(7540), money.New(5272), 0},
+		},
+		"TaxReducer": {
+			rates:     taxRates,
+			income:    money.New(35000),
+			allowance: money.New(12570),
+			reducer:   money.New(252),
+			taxable:   money.New(22430),
+			taxed:     money.New(4234),
+			bands:     []Money{money.New(4486), 0, 0},
+		},
+		"TaxReducerAboveTax": {
+			rates:     taxRates,
+			income:    money.New(13570),
+			allowance: money.New(12570),
+			reducer:   money.New(252),
+			taxable:   money.New(1000),
+			taxed:     0,
+			bands:     []Money{money.New(200), 0, 0},
 		},
 		"AdditionalRate": {
+			rates:     taxRates,
 			income:    money.New(143000),
 			allowance: 0,
-			expected: IncomeTaxBreakdown{
-				GrossIncome:    money.New(143000),
-				BasicRate:      money.New(7540.20),
-				HigherRate:     money.New(34976),
-				AdditionalRate: money.New(8036.55),
-				Taxable:        money.New(143000),
-				Taxed:          money.New(50552.75),
-			},
+			taxable:   money.New(143000),
+			taxed:     money.New(50553),
+			bands:     []Money{money.New(7540), money.New(34976), money.New(8037)},
+		},
+		"ScottishIntermediateRate": {
+			rates:     scottishTaxRates,
+			income:    money.New(35000),
+			allowance: money.New(12570),
+			taxable:   money.New(22430),
+			taxed:     money.New(4547.33),
+			bands:     []Money{money.New(438.14), money.New(2337), money.New(1772.19), 0, 0, 0},
+		},
+		"ScottishAdvancedRate": {
+			rates:     scottishTaxRates,
+			income:    money.New(80000),
+			allowance: money.New(12570),
+			taxable:   money.New(67430),
+			taxed:     money.New(21778.31),
+			bands:     []Money{money.New(438.14), money.New(2337), money.New(3591.21), money.New(13161.96), money.New(2250), 0},
+		},
+		"ScottishTopRate": {
+			rates:     scottishTaxRates,
+			income:    money.New(150000),
+			allowance: 0,
+			taxable:   money.New(150000),
+			taxed:     money.New(59680.61),
+			bands:     []Money{money.New(438.14), money.New(2337), money.New(3591.21), money.New(13161.96), money.New(28219.5), money.New(11932.8)},
 		},
 	}
 
-	tax := TaxCalculator{
-		IncomeTaxRates:         taxRates,
-		NationalInsuranceRates: niRates,
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := test.rates.calculateIncomeTax(test.income, test.allowance, test.reducer)
+
+			bands := make([]Money, len(actual.Bands))
+			for i, b := range actual.Bands {
+				bands[i] = b.Tax
+			}
+
+			if actual.GrossIncome != test.income || actual.Taxable != test.taxable || actual.Taxed != test.taxed {
+				t.Errorf("got {Taxable: %v, Taxed: %v}, want {Taxable: %v, Taxed: %v}", actual.Taxable, actual.Taxed, test.taxable, test.taxed)
+			}
+
+			if !reflect.DeepEqual(bands, test.bands) {
+				t.Errorf("got bands %v, want %v", bands, test.bands)
+			}
+		})
+	}
+}
+
+func TestNationalInsuranceConfig(t *testing.T) {
+	data, err := os.ReadFile("../../assets/config/national_insurance/2024_2025.json")
+	if err != nil {
+		t.Fatalf("an unexpected error was returned: %v", err)
 	}
 
+	rates := map[string]NationalInsuranceRates{}
+	err = json.Unmarshal(data, &rates)
+	if err != nil {
+		t.Fatalf("an unexpected error was returned: %v", err)
+	}
+
+	// The main rate is 8% from 6 April 2024, after the cut to 10% on
+	// 6 January 2024.
+	tests := map[string]struct {
+		category string
+		expected string
+	}{
+		"Standard": {
+			category: "A",
+			expected: "59.82",
+		},
+		"Under21": {
+			category: "M",
+			expected: "59.82",
+		},
+		"Deferred": {
+			category: "J",
+			expected: "16.32",
+		},
+	}
+
+	tax := TaxCalculator{NationalInsuranceRates: rates}
+
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			actual := tax.calculateIncomeTax(test.income, test.allowance)
+			_, actual, err := tax.calculateNationalInsurance(money.New(1058), test.category)
+			if err != nil {
+				t.Fatalf("an unexpected error was returned: %v", err)
+			}
 
-			if actual != test.expected {
-				t.Errorf("got %v, want %v", actual, test.expected)
+			if actual.Format(2) != test.expected {
+				t.Errorf("got %s, want %s", actual.Format(2), test.expected)
 			}
 		})
 	}
 }
 
+func TestIncomeTaxRatesLegacyConfig(t *testing.T) {
+	legacy := `{
+		"PersonalAllowance": 12570000000,
+		"PersonalAllowanceThreshold": 100000000000,
+		"Basic": {"Min": 12571000000, "Max": 50270000000, "Rate": 0.2},
+		"Higher": {"Min": 50271000000, "Max": 125140000000, "Rate": 0.4},
+		"Additional": {"Min": 125141000000, "Max": 0, "Rate": 0.45}
+	}`
+
+	actual := IncomeTaxRates{}
+	err := json.Unmarshal([]byte(legacy), &actual)
+	if err != nil {
+		t.Fatalf("an unexpected error was returned: %v", err)
+	}
+
+	if !reflect.DeepEqual(actual, taxRates) {
+		t.Errorf("got %v, want %v", actual, taxRates)
+	}
+}
+
 func TestTaxAllowance(t *testing.T) {
 	tests := map[string]struct {
 		income   Money
@@ -184,14 +461,9 @@ func TestTaxAllowance(t *testing.T) {
 		},
 	}
 
-	tax := TaxCalculator{
-		IncomeTaxRates:         taxRates,
-		NationalInsuranceRates: niRates,
-	}
-
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			actual := tax.calculateTaxAllowance(test.income)
+			actual := taxRates.calculateTaxAllowance(test.income)
 
 			if actual != test.expected {
 				t.Errorf("got %v, want %v", actual, test.expected)
@@ -203,57 +475,92 @@ func TestTaxAllowance(t *testing.T) {
 func TestTakeHome(t *testing.T) {
 	tests := map[string]struct {
 		income           Money
+		opts             Options
 		expectedTakeHome string
 		expectedNI       string
+		expectedCost     string
 	}{
 		"NoTax": {
 			income:           money.New(7543),
+			opts:             Options{Residency: RestOfUK, NICategory: "A"},
 			expectedTakeHome: "7543.00",
 			expectedNI:       "0.00",
+			expectedCost:     "7543.00",
 		},
 		"HigherRate": {
 			income:           money.New(63450),
-			expectedTakeHome: "46604.88",
+			opts:             Options{Residency: RestOfUK, NICategory: "A"},
+			expectedTakeHome: "46604.68",
 			expectedNI:       "4033.32",
+			expectedCost:     "70950.30",
+		},
+		"ScottishHigherRate": {
+			income:           money.New(63450),
+			opts:             Options{Residency: Scotland, NICategory: "A"},
+			expectedTakeHome: "44739.37",
+			expectedNI:       "4033.32",
+			expectedCost:     "70950.30",
+		},
+		"StudentAndPostgraduateLoans": {
+			income:           money.New(63450),
+			opts:             Options{Residency: RestOfUK, NICategory: "A", StudentLoan: Plan2, PostgraduateLoan: true},
+			expectedTakeHome: "40803.73",
+			expectedNI:       "4033.32",
+			expectedCost:     "70950.30",
 		},
 	}
 
 	tests_fail := map[string]struct {
-		niCategory string
+		opts Options
 	}{
 		"NIDoesntExist": {
-			niCategory: "ZZ",
+			opts: Options{Residency: RestOfUK, NICategory: "ZZ"},
 		},
 		"NIEmpty": {
-			niCategory: "",
+			opts: Options{Residency: RestOfUK, NICategory: ""},
+		},
+		"ResidencyDoesntExist": {
+			opts: Options{Residency: "Atlantis", NICategory: "A"},
+		},
+		"StudentLoanDoesntExist": {
+			opts: Options{Residency: RestOfUK, NICategory: "A", StudentLoan: "Plan 3"},
+		},
+		// The postgraduate loan is only repaid through its own option.
+		"PostgraduatePlan": {
+			opts: Options{Residency: RestOfUK, NICategory: "A", StudentLoan: Postgraduate, PostgraduateLoan: true},
 		},
 	}
 
 	tax := TaxCalculator{
-		IncomeTaxRates:         taxRates,
+		IncomeTaxRates: map[Residency]IncomeTaxRates{
+			RestOfUK: taxRates,
+			Scotland: scottishTaxRates,
+		},
 		NationalInsuranceRates: niRates,
+		StudentLoanRates:       studentLoanRates,
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			actual, _ := tax.CalculateTakeHome(test.income, "A")
+			actual, _ := tax.CalculateTakeHome(test.income, test.opts)
 
 			takeHome := actual.TakeHome.Format(2)
 			ni := actual.NationalInsurance.Format(2)
+			cost := actual.EmploymentCost.Format(2)
 
-			if takeHome != test.expectedTakeHome || ni != test.expectedNI {
-				t.Errorf("got {TakeHome: %s, National Insurance: %s}, want {TakeHome: %s, National Insurance: %s}",
-					takeHome, ni, test.expectedTakeHome, test.expectedNI)
+			if takeHome != test.expectedTakeHome || ni != test.expectedNI || cost != test.expectedCost {
+				t.Errorf("got {TakeHome: %s, National Insurance: %s, Cost: %s}, want {TakeHome: %s, National Insurance: %s, Cost: %s}",
+					takeHome, ni, cost, test.expectedTakeHome, test.expectedNI, test.expectedCost)
 			}
 		})
 	}
 
 	for name, test := range tests_fail {
 		t.Run(name, func(t *testing.T) {
-			actual, err := tax.CalculateTakeHome(0, test.niCategory)
+			actual, err := tax.CalculateTakeHome(0, test.opts)
 			expected := IncomeTaxBreakdown{}
 
-			if actual != expected || err == nil {
+			if !reflect.DeepEqual(actual, expected) || err == nil {
 				t.Error("an error was expected but not returned")
 			}
 		})
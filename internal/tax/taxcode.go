@@ -0,0 +1,81 @@
+package tax
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/vfc2/tax-calculator/internal/taxcode"
+)
+
+// Residency of the taxpayer given by the country prefix of a tax code.
+func residencyOf(country taxcode.Country) Residency {
+	switch country {
+	case taxcode.Scotland:
+		return Scotland
+	case taxcode.Wales:
+		return Wales
+	default:
+		return RestOfUK
+	}
+}
+
+// The country of the tax codes of a Residency.
+func countryOf(residency Residency) taxcode.Country {
+	switch residency {
+	case Scotland:
+		return taxcode.Scotland
+	case Wales:
+		return taxcode.Wales
+	default:
+		return taxcode.RestOfUK
+	}
+}
+
+// CheckTaxCode returns an error if a tax code cannot be applied to the
+// rates of the tax year, e.g. a Scottish D code above the bands of a year
+// without the advanced rate.
+func (t TaxCalculator) CheckTaxCode(code taxcode.Code) error {
+	residency := residencyOf(code.Country)
+	rates, ok := t.IncomeTaxRates[residency]
+	if !ok {
+		return fmt.Errorf("the requested %s residency does not exist", residency)
+	}
+
+	_, _, err := rates.applyTaxCode(code)
+
+	return err
+}
+
+// Apply a tax code to the rates and return the rates and the allowance
+// to use in place of the tapered personal allowance. BR and D codes tax
+// all income at a single rate, NT codes do not tax income.
+// Requirements from https://www.gov.uk/tax-codes/what-your-tax-code-means
+func (r IncomeTaxRates) applyTaxCode(code taxcode.Code) (IncomeTaxRates, Money, error) {
+	basic := slices.IndexFunc(r.Bands, func(b Band) bool {
+		return b.Name == basicRateBand
+	})
+
+	switch code.Kind {
+	case taxcode.Allowance, taxcode.Deduction:
+		return r, code.Allowance(), nil
+	case taxcode.BasicRate, taxcode.HigherRate:
+		i := basic
+		if code.Kind == taxcode.HigherRate {
+			i = basic + 1 + code.Level
+		}
+		if basic < 0 || i >= len(r.Bands) {
+			return r, 0, fmt.Errorf("the tax code %s has no matching band", code)
+		}
+
+		b := r.Bands[i]
+		r.Bands = []Band{{Name: b.Name, Min: 0, Max: 0, Rate: b.Rate}}
+
+		return r, 0, nil
+	case taxcode.NoTax:
+		r.Bands = nil
+
+		return r, 0, nil
+	default:
+		return r, 0, fmt.Errorf("the tax code %s is not supported", code)
+	}
+}
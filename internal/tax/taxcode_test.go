@@ -0,0 +1,154 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+	"github.com/vfc2/tax-calculator/internal/taxcode"
+)
+
+func TestTaxCodeTakeHome(t *testing.T) {
+	tests := map[string]struct {
+		code              string
+		expectedResidency Residency
+		expectedTaxed     string
+	}{
+		"Standard": {
+			code:              "1257L",
+			expectedResidency: RestOfUK,
+			expectedTaxed:     "12808.40",
+		},
+		"K": {
+			code:              "K475",
+			expectedResidency: RestOfUK,
+			expectedTaxed:     "19743.60",
+		},
+		"BasicRate": {
+			code:              "BR",
+			expectedResidency: RestOfUK,
+			expectedTaxed:     "12690.00",
+		},
+		"HigherRate": {
+			code:              "D0",
+			expectedResidency: RestOfUK,
+			expectedTaxed:     "25380.00",
+		},
+		"AdditionalRate": {
+			code:              "D1",
+			expectedResidency: RestOfUK,
+			expectedTaxed:     "28552.50",
+		},
+		"NoTax": {
+			code:              "NT",
+			expectedResidency: RestOfUK,
+			expectedTaxed:     "0.00",
+		},
+		"Welsh": {
+			code:              "C1257L",
+			expectedResidency: Wales,
+			expectedTaxed:     "12808.40",
+		},
+		"Scottish": {
+			code:              "S1257L",
+			expectedResidency: Scotland,
+			expectedTaxed:     "14673.53",
+		},
+		"ScottishTopRate": {
+			code:              "SD3",
+			expectedResidency: Scotland,
+			expectedTaxed:     "30456.00",
+		},
+	}
+
+	tax := TaxCalculator{
+		IncomeTaxRates: map[Residency]IncomeTaxRates{
+			RestOfUK: taxRates,
+			Wales:    taxRates,
+			Scotland: scottishTaxRates,
+		},
+		NationalInsuranceRates: niRates,
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			code, err := taxcode.Parse(test.code)
+			if err != nil {
+				t.Fatalf("an unexpected error was returned: %v", err)
+			}
+
+			opts := Options{Residency: RestOfUK, NICategory: "A", TaxCode: &code}
+			actual, _ := tax.CalculateTakeHome(money.New(63450), opts)
+
+			taxed := actual.Taxed.Format(2)
+
+			if actual.Residency != test.expectedResidency || taxed != test.expectedTaxed {
+				t.Errorf("got {Residency: %s, Taxed: %s}, want {Residency: %s, Taxed: %s}",
+					actual.Residency, taxed, test.expectedResidency, test.expectedTaxed)
+			}
+		})
+	}
+
+	code := taxcode.Code{Kind: taxcode.HigherRate, Level: 5}
+	_, err := tax.CalculateTakeHome(money.New(63450), Options{NICategory: "A", TaxCode: &code})
+	if err == nil {
+		t.Error("an error was expected but not returned")
+	}
+}
+
+func TestCheckTaxCode(t *testing.T) {
+	// The Scottish rates before the advanced rate.
+	scottish := IncomeTaxRates{
+		PersonalAllowance:          money.New(12570),
+		PersonalAllowanceThreshold: money.New(100000),
+		Bands: []Band{
+			{Name: "Starter", Min: 0, Max: money.New(2162), Rate: 0.19},
+			{Name: "Basic", Min: money.New(2162), Max: money.New(13118), Rate: 0.2},
+			{Name: "Intermediate", Min: money.New(13118), Max: money.New(31092), Rate: 0.21},
+			{Name: "Higher", Min: money.New(31092), Max: money.New(125140), Rate: 0.42},
+			{Name: "Top", Min: money.New(125140), Max: 0, Rate: 0.47},
+		},
+	}
+	tax := TaxCalculator{
+		IncomeTaxRates: map[Residency]IncomeTaxRates{
+			RestOfUK: taxRates,
+			Scotland: scottish,
+		},
+	}
+
+	tests := map[string]struct {
+		code string
+	}{
+		"Standard":       {code: "1257L"},
+		"AdditionalRate": {code: "D1"},
+		"ScottishTop":    {code: "SD2"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			code, _ := taxcode.Parse(test.code)
+
+			err := tax.CheckTaxCode(code)
+			if err != nil {
+				t.Errorf("an unexpected error was returned: %v", err)
+			}
+		})
+	}
+
+	tests_fail := map[string]struct {
+		code string
+	}{
+		"NoAdvancedRate": {code: "SD3"},
+		"NoWelshRates":   {code: "C1257L"},
+	}
+
+	for name, test := range tests_fail {
+		t.Run(name, func(t *testing.T) {
+			code, _ := taxcode.Parse(test.code)
+
+			err := tax.CheckTaxCode(code)
+			if err == nil {
+				t.Error("an error was expected but not returned")
+			}
+		})
+	}
+}
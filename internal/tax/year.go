@@ -0,0 +1,93 @@
+package tax
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// TaxYear is a UK tax year, from 6 April to 5 April, identified by the
+// calendar year it starts in.
+type TaxYear int
+
+var taxYearFormat = regexp.MustCompile(`^(\d{4})(?:[/_-](\d{2}|\d{4}))?$`)
+
+// TaxYearOf returns the tax year a date falls in.
+func TaxYearOf(date time.Time) TaxYear {
+	year := date.Year()
+	if date.Month() < time.April || date.Month() == time.April && date.Day() < 6 {
+		year--
+	}
+
+	return TaxYear(year)
+}
+
+// ParseTaxYear reads a tax year written as 2024, 2024/25, 2024-25 or
+// 2024_2025.
+func ParseTaxYear(s string) (TaxYear, error) {
+	m := taxYearFormat.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("the tax year %q is invalid", s)
+	}
+
+	start, _ := strconv.Atoi(m[1])
+	if m[2] != "" {
+		end, _ := strconv.Atoi(m[2])
+		if len(m[2]) == 2 {
+			end += start / 100 * 100
+			if end < start {
+				end += 100
+			}
+		}
+		if end != start+1 {
+			return 0, fmt.Errorf("the tax year %q does not span consecutive years", s)
+		}
+	}
+
+	return TaxYear(start), nil
+}
+
+// Start returns the first day of the tax year.
+func (y TaxYear) Start() time.Time {
+	return time.Date(int(y), time.April, 6, 0, 0, 0, 0, time.UTC)
+}
+
+// End returns the last day of the tax year.
+func (y TaxYear) End() time.Time {
+	return time.Date(int(y)+1, time.April, 5, 0, 0, 0, 0, time.UTC)
+}
+
+func (y TaxYear) String() string {
+	return fmt.Sprintf("%d/%02d", int(y), (int(y)+1)%100)
+}
+
+// Registry holds the TaxCalculator of each tax year.
+type Registry map[TaxYear]TaxCalculator
+
+// Years returns the tax years of the registry in order.
+func (r Registry) Years() []TaxYear {
+	years := make([]TaxYear, 0, len(r))
+	for y := range r {
+		years = append(years, y)
+	}
+	slices.Sort(years)
+
+	return years
+}
+
+// Calculator returns the TaxCalculator of a tax year.
+func (r Registry) Calculator(year TaxYear) (TaxCalculator, error) {
+	t, ok := r[year]
+	if !ok {
+		return TaxCalculator{}, fmt.Errorf("the rates for the tax year %s are not available", year)
+	}
+
+	return t, nil
+}
+
+// CalculatorAt returns the TaxCalculator of the tax year a date falls in.
+func (r Registry) CalculatorAt(date time.Time) (TaxCalculator, error) {
+	return r.Calculator(TaxYearOf(date))
+}
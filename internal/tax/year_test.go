@@ -0,0 +1,112 @@
+package tax
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaxYearOf(t *testing.T) {
+	tests := map[string]struct {
+		date     time.Time
+		expected TaxYear
+	}{
+		"FirstDay": {
+			date:     time.Date(2024, time.April, 6, 0, 0, 0, 0, time.UTC),
+			expected: 2024,
+		},
+		"LastDay": {
+			date:     time.Date(2025, time.April, 5, 23, 59, 0, 0, time.UTC),
+			expected: 2024,
+		},
+		"January": {
+			date:     time.Date(2024, time.January, 6, 0, 0, 0, 0, time.UTC),
+			expected: 2023,
+		},
+		"December": {
+			date:     time.Date(2024, time.December, 25, 0, 0, 0, 0, time.UTC),
+			expected: 2024,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := TaxYearOf(test.date)
+
+			if actual != test.expected {
+				t.Errorf("got %s, want %s", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseTaxYear(t *testing.T) {
+	tests := map[string]struct {
+		year     string
+		expected TaxYear
+	}{
+		"Start":     {year: "2024", expected: 2024},
+		"Short":     {year: "2024/25", expected: 2024},
+		"Dash":      {year: "2024-25", expected: 2024},
+		"FileName":  {year: "2024_2025", expected: 2024},
+		"Centenary": {year: "2099/00", expected: 2099},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, err := ParseTaxYear(test.year)
+			if err != nil {
+				t.Fatalf("an unexpected error was returned: %v", err)
+			}
+
+			if actual != test.expected {
+				t.Errorf("got %s, want %s", actual, test.expected)
+			}
+
+			if actual.String() != test.expected.String() {
+				t.Errorf("got %s, want %s", actual.String(), test.expected.String())
+			}
+		})
+	}
+
+	tests_fail := map[string]string{
+		"Empty":          "",
+		"Text":           "last year",
+		"NotConsecutive": "2024/26",
+		"Backwards":      "2024_2023",
+	}
+
+	for name, year := range tests_fail {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseTaxYear(year)
+
+			if err == nil {
+				t.Error("an error was expected but not returned")
+			}
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	registry := Registry{
+		2024: {IncomeTaxRates: map[Residency]IncomeTaxRates{RestOfUK: taxRates}},
+		2023: {},
+	}
+
+	years := registry.Years()
+	if len(years) != 2 || years[0] != 2023 || years[1] != 2024 {
+		t.Errorf("got %v, want [2023/24 2024/25]", years)
+	}
+
+	calc, err := registry.CalculatorAt(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("an unexpected error was returned: %v", err)
+	}
+	if _, ok := calc.IncomeTaxRates[RestOfUK]; !ok {
+		t.Error("got the calculator of another tax year, want 2024/25")
+	}
+
+	_, err = registry.Calculator(2025)
+	if err == nil {
+		t.Error("an error was expected but not returned")
+	}
+}
@@ -0,0 +1,219 @@
+// Package taxcode implements a parser for HMRC PAYE tax codes.
+//
+// A tax code is made of an optional country prefix (S for Scotland,
+// C for Wales), a body and an optional emergency suffix (W1, M1 or X).
+// The body is either a number followed by a letter (1257L, 0T), a K code
+// (K475) or a special code (BR, D0, D1, NT).
+// Requirements from https://www.gov.uk/tax-codes/what-your-tax-code-means
+package taxcode
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+)
+
+// Country is the part of the UK the tax code applies to.
+type Country string
+
+const (
+	RestOfUK Country = ""
+	Scotland Country = "S"
+	Wales    Country = "C"
+)
+
+// Kind is how a tax code determines the tax due.
+type Kind int
+
+const (
+	// Allowance codes give a tax-free amount, e.g. 1257L or 0T.
+	Allowance Kind = iota
+	// Deduction codes add an amount to the taxable pay, e.g. K475.
+	Deduction
+	// BasicRate taxes all income at the basic rate, e.g. BR.
+	BasicRate
+	// HigherRate taxes all income at a rate above basic, e.g. D0.
+	HigherRate
+	// NoTax means no tax is taken, e.g. NT.
+	NoTax
+)
+
+// errLeadingZero is returned for a tax code number starting with 0.
+var errLeadingZero = errors.New("the number starts with 0")
+
+// Error describes why a tax code is invalid.
+type Error struct {
+	Code   string
+	Reason string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid tax code %q: %s", e.Code, e.Reason)
+}
+
+// Code is a parsed tax code.
+type Code struct {
+	Country Country
+	Kind    Kind
+	// Number of Allowance and Deduction codes, e.g. 1257 for 1257L.
+	Number int
+	// Letter of Allowance codes, e.g. L for 1257L.
+	Letter string
+	// Level of HigherRate codes, e.g. 1 for D1.
+	Level int
+	// NonCumulative is set for emergency codes (W1, M1 or X).
+	NonCumulative bool
+}
+
+// Parse returns the Code of a tax code string. Spaces are ignored and
+// letters are case insensitive. An Error is returned when the code is
+// invalid.
+func Parse(code string) (Code, error) {
+	raw := strings.ToUpper(strings.Join(strings.Fields(code), ""))
+	c := Code{}
+
+	if raw == "" {
+		return c, &Error{Code: code, Reason: "the tax code is empty"}
+	}
+
+	body := raw
+	for _, suffix := range []string{"/W1", "/M1", "W1", "M1", "X"} {
+		if strings.HasSuffix(body, suffix) {
+			body = strings.TrimSuffix(body, suffix)
+			c.NonCumulative = true
+			break
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(body, "S"):
+		c.Country = Scotland
+		body = body[1:]
+	case strings.HasPrefix(body, "C"):
+		c.Country = Wales
+		body = body[1:]
+	}
+
+	if body == "" {
+		return Code{}, &Error{Code: code, Reason: "the tax code has no number or rate"}
+	}
+
+	switch {
+	case body == "BR":
+		c.Kind = BasicRate
+	case body == "NT":
+		c.Kind = NoTax
+	case body[0] == 'D':
+		if len(body) != 2 || body[1] < '0' || body[1] > '9' {
+			return Code{}, &Error{Code: code, Reason: "a D code must be followed by a single digit, e.g. D0"}
+		}
+		level := int(body[1] - '0')
+
+		limit := 1
+		if c.Country == Scotland {
+			limit = 3
+		}
+		if level > limit {
+			return Code{}, &Error{Code: code, Reason: fmt.Sprintf("D%d is not a valid rate, the highest is D%d", level, limit)}
+		}
+
+		c.Kind = HigherRate
+		c.Level = level
+	case body[0] == 'K':
+		number, err := parseNumber(body[1:])
+		if errors.Is(err, errLeadingZero) {
+			return Code{}, &Error{Code: code, Reason: "the number of a K code cannot start with 0"}
+		}
+		if err != nil {
+			return Code{}, &Error{Code: code, Reason: "a K code must be followed by a number, e.g. K475"}
+		}
+		if number == 0 {
+			return Code{}, &Error{Code: code, Reason: "the number of a K code cannot be 0"}
+		}
+
+		c.Kind = Deduction
+		c.Number = number
+	default:
+		letter := body[len(body)-1:]
+		if strings.Contains("0123456789", letter) {
+			return Code{}, &Error{Code: code, Reason: "the tax code must end with a letter, e.g. 1257L"}
+		}
+		if !strings.Contains("LMNT", letter) {
+			return Code{}, &Error{Code: code, Reason: fmt.Sprintf("the letter %s is not valid, it must be one of L, M, N or T", letter)}
+		}
+
+		number, err := parseNumber(body[:len(body)-1])
+		if errors.Is(err, errLeadingZero) {
+			return Code{}, &Error{Code: code, Reason: "the number of the tax code cannot start with 0"}
+		}
+		if err != nil {
+			return Code{}, &Error{Code: code, Reason: "the tax code must start with a number, e.g. 1257L"}
+		}
+
+		c.Kind = Allowance
+		c.Number = number
+		c.Letter = letter
+	}
+
+	return c, nil
+}
+
+// Parse a tax code number made of digits only, 0 being the only number
+// starting with 0.
+func parseNumber(s string) (int, error) {
+	if s == "" || strings.TrimLeft(s, "0123456789") != "" {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	if len(s) > 1 && s[0] == '0' {
+		return 0, errLeadingZero
+	}
+
+	return strconv.Atoi(s)
+}
+
+// Allowance returns the yearly tax-free amount of the code, negative for
+// K codes. The code number is multiplied by 10 and 9 is added, other
+// than for 0T which has no allowance.
+// Requirements from https://www.gov.uk/government/publications/paye-tax-tables
+func (c Code) Allowance() money.Money {
+	if c.Number == 0 {
+		return 0
+	}
+
+	switch c.Kind {
+	case Allowance:
+		return money.New(c.Number*10 + 9)
+	case Deduction:
+		return -money.New(c.Number*10 + 9)
+	default:
+		return 0
+	}
+}
+
+// String returns the tax code in its canonical form, e.g. S1257L W1.
+func (c Code) String() string {
+	var body string
+
+	switch c.Kind {
+	case Allowance:
+		body = strconv.Itoa(c.Number) + c.Letter
+	case Deduction:
+		body = "K" + strconv.Itoa(c.Number)
+	case BasicRate:
+		body = "BR"
+	case HigherRate:
+		body = "D" + strconv.Itoa(c.Level)
+	case NoTax:
+		body = "NT"
+	}
+
+	s := string(c.Country) + body
+	if c.NonCumulative {
+		s += " W1"
+	}
+
+	return s
+}
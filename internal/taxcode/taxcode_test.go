@@ -0,0 +1,160 @@
+package taxcode
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vfc2/tax-calculator/internal/money"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		code      string
+		expected  Code
+		allowance money.Money
+	}{
+		"Standard": {
+			code:      "1257L",
+			expected:  Code{Kind: Allowance, Number: 1257, Letter: "L"},
+			allowance: money.New(12579),
+		},
+		"MarriageAllowance": {
+			code:      "1383m",
+			expected:  Code{Kind: Allowance, Number: 1383, Letter: "M"},
+			allowance: money.New(13839),
+		},
+		"NoAllowance": {
+			code:      "0T",
+			expected:  Code{Kind: Allowance, Number: 0, Letter: "T"},
+			allowance: 0,
+		},
+		"K": {
+			code:      "K475",
+			expected:  Code{Kind: Deduction, Number: 475},
+			allowance: money.New(-4759),
+		},
+		"BasicRate": {
+			code:     "BR",
+			expected: Code{Kind: BasicRate},
+		},
+		"HigherRate": {
+			code:     "D0",
+			expected: Code{Kind: HigherRate, Level: 0},
+		},
+		"AdditionalRate": {
+			code:     "D1",
+			expected: Code{Kind: HigherRate, Level: 1},
+		},
+		"NoTax": {
+			code:     "NT",
+			expected: Code{Kind: NoTax},
+		},
+		"Scottish": {
+			code:      "S1257L",
+			expected:  Code{Country: Scotland, Kind: Allowance, Number: 1257, Letter: "L"},
+			allowance: money.New(12579),
+		},
+		"ScottishTopRate": {
+			code:     "SD3",
+			expected: Code{Country: Scotland, Kind: HigherRate, Level: 3},
+		},
+		"Welsh": {
+			code:      "CK100",
+			expected:  Code{Country: Wales, Kind: Deduction, Number: 100},
+			allowance: money.New(-1009),
+		},
+		"EmergencyWeek1": {
+			code:      "1257L W1",
+			expected:  Code{Kind: Allowance, Number: 1257, Letter: "L", NonCumulative: true},
+			allowance: money.New(12579),
+		},
+		"EmergencyMonth1": {
+			code:      "s1257l/m1",
+			expected:  Code{Country: Scotland, Kind: Allowance, Number: 1257, Letter: "L", NonCumulative: true},
+			allowance: money.New(12579),
+		},
+		"EmergencyX": {
+			code:     "BR X",
+			expected: Code{Kind: BasicRate, NonCumulative: true},
+		},
+	}
+
+	tests_fail := map[string]struct {
+		code string
+	}{
+		"Empty":            {code: " "},
+		"PrefixOnly":       {code: "S"},
+		"NoLetter":         {code: "1257"},
+		"UnknownLetter":    {code: "1257P"},
+		"NoNumber":         {code: "L"},
+		"InvalidNumber":    {code: "12A7L"},
+		"KWithoutNumber":   {code: "K"},
+		"DOutOfRange":      {code: "D2"},
+		"DScottishInRange": {code: "SD4"},
+		"DNotANumber":      {code: "DX1"},
+		"DLeadingZero":     {code: "D01"},
+		"DSigned":          {code: "D+1"},
+		"LeadingZero":      {code: "01257L"},
+		"KLeadingZero":     {code: "K0475"},
+		"KZero":            {code: "K0"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, err := Parse(test.code)
+
+			if err != nil {
+				t.Fatalf("an unexpected error was returned: %v", err)
+			}
+
+			if actual != test.expected || actual.Allowance() != test.allowance {
+				t.Errorf("got %v (allowance %v), want %v (allowance %v)", actual, actual.Allowance(), test.expected, test.allowance)
+			}
+		})
+	}
+
+	for name, test := range tests_fail {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(test.code)
+
+			var e *Error
+			if !errors.As(err, &e) || e.Reason == "" {
+				t.Errorf("a validation error was expected but got %v", err)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := map[string]struct {
+		code     Code
+		expected string
+	}{
+		"Standard": {
+			code:     Code{Kind: Allowance, Number: 1257, Letter: "L"},
+			expected: "1257L",
+		},
+		"ScottishEmergency": {
+			code:     Code{Country: Scotland, Kind: Allowance, Number: 1257, Letter: "L", NonCumulative: true},
+			expected: "S1257L W1",
+		},
+		"K": {
+			code:     Code{Country: Wales, Kind: Deduction, Number: 475},
+			expected: "CK475",
+		},
+		"HigherRate": {
+			code:     Code{Kind: HigherRate, Level: 1},
+			expected: "D1",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := test.code.String()
+
+			if actual != test.expected {
+				t.Errorf("got %v, want %v", actual, test.expected)
+			}
+		})
+	}
+}